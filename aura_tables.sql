@@ -15,6 +15,7 @@ CREATE TABLE IF NOT EXISTS wallets (
 CREATE TABLE IF NOT EXISTS subscriptions (
     user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
     wallet_id INTEGER REFERENCES wallets(id),
+    wallet_address TEXT NOT NULL,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     PRIMARY KEY (user_id, wallet_id)
 );
@@ -22,9 +23,78 @@ CREATE TABLE IF NOT EXISTS subscriptions (
 CREATE TABLE IF NOT EXISTS tokens (
     id INTEGER GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
     token_address TEXT NOT NULL UNIQUE,
-    token_name NOT NULL TEXT,
-    token_symbol NOT NULL TEXT,
-    token_supply NOT NULL DECIMAL,
+    token_name TEXT NOT NULL,
+    token_symbol TEXT NOT NULL,
+    token_supply DECIMAL NOT NULL,
+    circulating_supply DECIMAL,
     created_at TIMESTAMP,
-    token_social TEXT
-);
\ No newline at end of file
+    age_source TEXT,
+    token_image TEXT,
+    token_description TEXT,
+    token_website TEXT,
+    token_twitter TEXT,
+    token_telegram TEXT,
+    token_program TEXT,
+    token_extensions JSONB,
+    token_price DECIMAL,
+    price_provider TEXT,
+    price_updated_at TIMESTAMP,
+    liquidity_usd DECIMAL,
+    market_updated_at TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS trades (
+    signature TEXT NOT NULL,
+    wallet_address TEXT NOT NULL,
+    slot BIGINT NOT NULL,
+    block_time TIMESTAMP,
+    input_mint TEXT NOT NULL,
+    input_amount DECIMAL NOT NULL,
+    output_mint TEXT NOT NULL,
+    output_amount DECIMAL NOT NULL,
+    venue TEXT,
+    usd_value DECIMAL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (signature, wallet_address)
+);
+
+CREATE INDEX IF NOT EXISTS trades_wallet_slot_idx ON trades (wallet_address, slot DESC, signature DESC);
+
+CREATE TABLE IF NOT EXISTS backfill_jobs (
+    wallet_address TEXT PRIMARY KEY,
+    status TEXT NOT NULL DEFAULT 'running',
+    before_signature TEXT,
+    processed INTEGER NOT NULL DEFAULT 0,
+    max_txns INTEGER NOT NULL,
+    cutoff TIMESTAMP NOT NULL,
+    failed_signatures TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- upgrades for databases created from an earlier version of this file
+ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS wallet_address TEXT;
+UPDATE subscriptions SET wallet_address = wallets.wallet_address
+    FROM wallets WHERE subscriptions.wallet_id = wallets.id AND subscriptions.wallet_address IS NULL;
+ALTER TABLE subscriptions ALTER COLUMN wallet_address SET NOT NULL;
+ALTER TABLE backfill_jobs ADD COLUMN IF NOT EXISTS failed_signatures TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE tokens ADD COLUMN IF NOT EXISTS circulating_supply DECIMAL;
+ALTER TABLE tokens ADD COLUMN IF NOT EXISTS token_image TEXT;
+ALTER TABLE tokens ADD COLUMN IF NOT EXISTS token_description TEXT;
+ALTER TABLE tokens ADD COLUMN IF NOT EXISTS token_website TEXT;
+ALTER TABLE tokens ADD COLUMN IF NOT EXISTS token_twitter TEXT;
+ALTER TABLE tokens ADD COLUMN IF NOT EXISTS token_telegram TEXT;
+ALTER TABLE tokens ADD COLUMN IF NOT EXISTS token_program TEXT;
+ALTER TABLE tokens ADD COLUMN IF NOT EXISTS token_extensions JSONB;
+ALTER TABLE tokens ADD COLUMN IF NOT EXISTS token_price DECIMAL;
+ALTER TABLE tokens ADD COLUMN IF NOT EXISTS price_provider TEXT;
+ALTER TABLE tokens ADD COLUMN IF NOT EXISTS price_updated_at TIMESTAMP;
+ALTER TABLE tokens ADD COLUMN IF NOT EXISTS liquidity_usd DECIMAL;
+ALTER TABLE tokens ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP;
+-- created_at values stored without an age_source came from a single page of signatures and may be wrong,
+-- they are ignored on read and replaced by the next age lookup
+ALTER TABLE tokens ADD COLUMN IF NOT EXISTS age_source TEXT;
+-- circulating_supply and liquidity_usd are remeasured once market_updated_at is stale,
+-- rows stored before it existed are remeasured on their next read
+ALTER TABLE tokens ADD COLUMN IF NOT EXISTS market_updated_at TIMESTAMP;
@@ -7,6 +7,8 @@ import (
 	"context"
 	"log"
 	"net/http"
+	"os"
+	"time"
 
 	"github.com/jakobsym/aura/internal/handler"
 	"github.com/jakobsym/aura/internal/repository/postgres"
@@ -25,21 +27,49 @@ func main() {
 	wsConnection := solana.SolanaWebSocketConnection()
 	defer wsConnection.Close()
 
-	// Init wallet tracking dependencies
-	solanaAccountRepo := solana.NewSolanaWebSocketRepo(wsConnection)
-	solanaAccountRepo.StartReader(context.Background()) // generalized reader for WS connection
-	accountPsqlRepo := postgres.NewPostgresAccountRepo(db)
-	solanaAccountService := service.NewAccountService(solanaAccountRepo, accountPsqlRepo)
-	accountHandler := handler.NewAccountHandler(solanaAccountService)
+	// Init token metadata resolver shared by the Solana repos
+	metadataResolver := solana.NewTokenMetadataResolver(rpcConnection, 4096)
+
+	// Init price providers, falling back from Jupiter to DexScreener to on-chain pool state
+	// HTTP APIs fail fast, on-chain pool scans are given longer
+	priceProvider := solana.NewPriceChain(
+		solana.ChainedProvider{Provider: solana.NewJupiterPriceProvider(), Timeout: 2 * time.Second},
+		solana.ChainedProvider{Provider: solana.NewDexScreenerPriceProvider(), Timeout: 2 * time.Second},
+		solana.ChainedProvider{Provider: solana.NewOnChainPriceProvider(rpcConnection), Timeout: 8 * time.Second},
+	)
 
 	// Init token dependencies
-	solanaTokenRepo := solana.NewSolanaTokenRepo(rpcConnection)
+	solanaTokenRepo := solana.NewSolanaTokenRepo(rpcConnection, metadataResolver, priceProvider)
 	psqlTokenRepo := postgres.NewPostgresTokenRepo(db)
-	tokenService := service.NewTokenService(psqlTokenRepo, solanaTokenRepo)
+
+	// Init quote currency conversion, through SOL / USDC prices and a fiat FX source
+	quoteConverter := service.NewQuoteConverter(solanaTokenRepo, solana.NewFXProvider(os.Getenv("FX_RATES_URL")))
+
+	// Init per token circulating supply exclusions (locked / vesting accounts)
+	supplyExclusions, err := service.LoadSupplyExclusions(os.Getenv("SUPPLY_EXCLUSIONS_FILE"))
+	if err != nil {
+		log.Fatalf("failed to load supply exclusions: %v", err)
+	}
+
+	tokenService := service.NewTokenService(psqlTokenRepo, solanaTokenRepo, quoteConverter, supplyExclusions)
 	tokenHandler := handler.NewTokenHandler(tokenService)
 
+	// Init wallet activity dependencies
+	tradePsqlRepo := postgres.NewPostgresTradeRepo(db)
+	solanaWalletRepo := solana.NewSolanaWalletRepo(rpcConnection)
+	walletService := service.NewWalletService(tradePsqlRepo, solanaTokenRepo, solanaWalletRepo, quoteConverter)
+	walletHandler := handler.NewWalletHandler(walletService)
+
+	// Init wallet tracking dependencies
+	solanaAccountRepo := solana.NewSolanaWebSocketRepo(wsConnection, metadataResolver)
+	solanaAccountRepo.StartReader(context.Background()) // generalized reader for WS connection
+	accountPsqlRepo := postgres.NewPostgresAccountRepo(db)
+	backfillPsqlRepo := postgres.NewPostgresBackfillRepo(db)
+	solanaAccountService := service.NewAccountService(solanaAccountRepo, accountPsqlRepo, tradePsqlRepo, solanaTokenRepo, backfillPsqlRepo, solana.NewSOLPriceHistory())
+	accountHandler := handler.NewAccountHandler(solanaAccountService)
+
 	// Config HTTP routes
-	router := routes.NewRouter(tokenHandler, accountHandler)
+	router := routes.NewRouter(tokenHandler, accountHandler, walletHandler)
 	ctx := context.Background()
 
 	log.Println("service running on 3000")
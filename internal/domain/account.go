@@ -1,6 +1,11 @@
 // Package `domain` contains structs and types used throughout application
 package domain
 
+import (
+	"encoding/json"
+	"time"
+)
+
 // Represents standard JSON-RPC request format for Helius API calls
 type HeliusRequest struct {
 	JsonRPC string `json:"jsonrpc"`
@@ -20,10 +25,11 @@ type CommitmentConfig struct {
 }
 
 // Represents response for subscription request(s)
+// Result holds a subscription ID for subscribe requests, and a bool for unsubscribe requests
 type HeliusSubscriptionResponse struct {
-	JsonRPC string `json:"jsonrpc"`
-	Result  int    `json:"result"`
-	ID      int    `json:"id"`
+	JsonRPC string          `json:"jsonrpc"`
+	Result  json.RawMessage `json:"result"`
+	ID      int             `json:"id"`
 	Error   *struct {
 		Code    int    `json:"code"`
 		Message string `json:"message"`
@@ -50,51 +56,127 @@ type User struct {
 	TelegramId int `json:"user_id"`
 }
 
-// Contains parsed transaction data w/ token balance changes
+// Represents a classified txn detected on a tracked wallet
+// Recipients holds the telegramIds of users subscribed to the wallet
+type WalletNotification struct {
+	WalletAddress string    `json:"wallet_address"`
+	Signature     string    `json:"signature"`
+	Slot          uint64    `json:"slot"`
+	BlockTime     time.Time `json:"block_time"`
+	Event         TxnEvent  `json:"event"`
+	Recipients    []int     `json:"recipients"`
+}
+
+// Represents an actively tracked wallet and the users subscribed to it
+type WalletSubscription struct {
+	WalletAddress string `json:"wallet_address"`
+	UserIds       []int  `json:"user_ids"`
+}
+
+// Contains a page of signatures for transactions involving an address, newest first
+type SignaturesResult struct {
+	Result []SignatureInfo `json:"result"`
+}
+
+// Represents a single confirmed transaction signature
+// Err is non-nil when the transaction failed
+type SignatureInfo struct {
+	Signature string `json:"signature"`
+	Slot      uint64 `json:"slot"`
+	BlockTime int64  `json:"blockTime"`
+	Err       any    `json:"err"`
+}
+
+// Contains parsed transaction data w/ lamport and token balance changes
+// LoadedAddresses holds the accounts resolved from address lookup tables (v0 txns)
 type TransactionResult struct {
 	Result struct {
-		Meta struct {
-			PreTokenBalances  []TokenBalance `json:"pre_token_balances"`
-			PostTokenBalances []TokenBalance `json:"post_token_balances"`
+		Slot      uint64 `json:"slot"`
+		BlockTime int64  `json:"blockTime"`
+		Meta      struct {
+			Err               any             `json:"err"`
+			Fee               uint64          `json:"fee"`
+			PreBalances       []uint64        `json:"preBalances"`
+			PostBalances      []uint64        `json:"postBalances"`
+			PreTokenBalances  []TokenBalance  `json:"preTokenBalances"`
+			PostTokenBalances []TokenBalance  `json:"postTokenBalances"`
+			LoadedAddresses   LoadedAddresses `json:"loadedAddresses"`
+			InnerInstructions []struct {
+				Index        int                   `json:"index"`
+				Instructions []CompiledInstruction `json:"instructions"`
+			} `json:"innerInstructions"`
+			LogMessages []string `json:"logMessages"`
 		} `json:"meta"`
 		Transaction struct {
 			Message struct {
-				AccountKeys []string `json:"account_keys"`
+				AccountKeys  []string              `json:"accountKeys"`
+				Instructions []CompiledInstruction `json:"instructions"`
 			} `json:"message"`
 		} `json:"transaction"`
 	} `json:"result"`
 }
 
+// Represents an instruction referencing accounts by their index in the full account key list
+type CompiledInstruction struct {
+	ProgramIDIndex int    `json:"programIdIndex"`
+	Accounts       []int  `json:"accounts"`
+	Data           string `json:"data"`
+}
+
+// Writable and readonly accounts loaded from address lookup tables
+type LoadedAddresses struct {
+	Writable []string `json:"writable"`
+	Readonly []string `json:"readonly"`
+}
+
 // Represents token balance in float64 format
 type UITokenAmount struct {
 	UIAmount float64 `json:"uiAmount"`
+	Decimals int     `json:"decimals"`
 }
 
 // Contains token ownership information
 type TokenBalance struct {
-	AccountIndex  int           `json:"account_index"`
+	AccountIndex  int           `json:"accountIndex"`
 	Mint          string        `json:"mint"`
 	Owner         string        `json:"owner"`
-	UITokenAmount UITokenAmount `json:"ui_token_amount"`
+	UITokenAmount UITokenAmount `json:"uiTokenAmount"`
 }
 
+// Venues that execute swaps, identified by program ID
+const (
+	VenueJupiter       = "Jupiter"
+	VenueRaydiumAMM    = "Raydium AMM"
+	VenueRaydiumCLMM   = "Raydium CLMM"
+	VenueRaydiumCPMM   = "Raydium CPMM"
+	VenueOrcaWhirlpool = "Orca Whirlpool"
+	VenueMeteora       = "Meteora"
+	VenueMeteoraDLMM   = "Meteora DLMM"
+	VenuePumpFun       = "Pump.fun"
+	VenuePumpFunAMM    = "Pump.fun AMM"
+)
+
 // Represents outcome of a token swap operation
+// Legs lists every net balance change when a trade touches more than one mint per side
+// Venue is the program that executed the trade, Router the aggregator that routed it (if any)
 type SwapResult struct {
-	SentAmount      float64 `json:"sent_amount"`
-	SentSymbol      string  `json:"sen_symbol"`
-	SentAddress     string  `json:"sent_address"`
-	ReceivedAddress string  `json:"received_address"`
-	ReceivedAmount  float64 `json:"received_amount"`
-	ReceivedSymbol  string  `json:"received_symbol"`
+	SentAmount      float64   `json:"sent_amount"`
+	SentSymbol      string    `json:"sen_symbol"`
+	SentAddress     string    `json:"sent_address"`
+	ReceivedAddress string    `json:"received_address"`
+	ReceivedAmount  float64   `json:"received_amount"`
+	ReceivedSymbol  string    `json:"received_symbol"`
+	Legs            []SwapLeg `json:"legs,omitempty"`
+	Venue           string    `json:"venue,omitempty"`
+	Router          string    `json:"router,omitempty"`
 }
 
-/*
-** deprecated **
- */
-type HeliusUnsubscribeResponse struct {
-	JsonRPC string `json:"jsonrpc"`
-	Result  bool   `json:"result"`
-	ID      int    `json:"id"`
+// Represents a single mint's net balance change within a swap
+// Amount is negative when sent, positive when received
+type SwapLeg struct {
+	Address string  `json:"address"`
+	Symbol  string  `json:"symbol"`
+	Amount  float64 `json:"amount"`
 }
 
 /*
@@ -0,0 +1,24 @@
+// Package `domain` contains structs and types used throughout application
+package domain
+
+import "time"
+
+// backfill job statuses
+const (
+	BackfillRunning = "running"
+	BackfillDone    = "done"
+)
+
+// `BackfillJob` represents the progress of importing a wallet's historical trades
+// Before is the last signature processed, the job pages backwards from it on resume.
+// The job stops after MaxTxns transactions or once it reaches txns older than Cutoff.
+// Failed holds the signatures of txns that could not be fetched, classified or stored, and were skipped.
+type BackfillJob struct {
+	WalletAddress string
+	Status        string
+	Before        string
+	Processed     int
+	MaxTxns       int
+	Cutoff        time.Time
+	Failed        []string
+}
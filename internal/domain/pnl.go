@@ -0,0 +1,39 @@
+// Package `domain` contains structs and types used throughout application
+package domain
+
+import "errors"
+
+// cost basis methods
+const (
+	PnLFIFO    = "fifo"
+	PnLAverage = "average"
+)
+
+var (
+	// `ErrInvalidPnLMethod` returned when a cost basis method is not supported
+	ErrInvalidPnLMethod = errors.New("invalid pnl method, expected fifo or average")
+	// `ErrInvalidPnLQuote` returned when pnl is requested in a quote other than USD
+	ErrInvalidPnLQuote = errors.New("invalid quote, pnl is only tracked in USD")
+)
+
+// `TokenPosition` represents a wallet's position and PnL in a single token, valued in its WalletPnL's Quote
+// AvgEntry is the cost basis per token of the quantity still held
+type TokenPosition struct {
+	Mint          string  `json:"mint"`
+	Quantity      float64 `json:"quantity"`
+	AvgEntry      float64 `json:"avg_entry"`
+	CostBasis     float64 `json:"cost_basis"`
+	Price         float64 `json:"price"`
+	RealizedPnL   float64 `json:"realized_pnl"`
+	UnrealizedPnL float64 `json:"unrealized_pnl"`
+}
+
+// `WalletPnL` represents the realized and unrealized PnL of a wallet's trades
+type WalletPnL struct {
+	WalletAddress string          `json:"wallet_address"`
+	Method        string          `json:"method"`
+	Quote         string          `json:"quote"`
+	Positions     []TokenPosition `json:"positions"`
+	RealizedPnL   float64         `json:"realized_pnl"`
+	UnrealizedPnL float64         `json:"unrealized_pnl"`
+}
@@ -0,0 +1,46 @@
+// Package `domain` contains structs and types used throughout application
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// well-known mints prices are quoted against
+const (
+	WrappedSOLMint = "So11111111111111111111111111111111111111112" // native SOL is reported under it
+	USDCMint       = "EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v"
+	USDTMint       = "Es9vMFrzaCERmJfrF4H2FYD4KCoNkY11McCe8BenwNYB"
+)
+
+// `USDStablecoins` holds stablecoin mints valued 1:1 against USD
+var USDStablecoins = map[string]bool{
+	USDCMint: true,
+	USDTMint: true,
+}
+
+// quote currencies prices and values can be denominated in
+const (
+	QuoteUSD  = "USD"
+	QuoteSOL  = "SOL"
+	QuoteUSDC = "USDC"
+	QuoteEUR  = "EUR"
+)
+
+var (
+	// `ErrPriceNotFound` returned when a provider has no price for a token
+	ErrPriceNotFound = errors.New("price not found")
+	// `ErrInvalidQuote` returned when a quote currency is not supported
+	ErrInvalidQuote = errors.New("invalid quote, expected USD, SOL, USDC or EUR")
+)
+
+// `TokenPrice` represents a token's USD price as quoted by a price provider
+type TokenPrice struct {
+	Mint     string    `json:"mint"`
+	Price    float64   `json:"price"`
+	Provider string    `json:"provider"`
+	QuotedAt time.Time `json:"quoted_at"`
+	// pool the price was read from and the USD value it holds, when the provider reports them
+	Pool      string  `json:"pool,omitempty"`
+	Liquidity float64 `json:"liquidity_usd,omitempty"`
+}
@@ -0,0 +1,85 @@
+// Package `domain` contains structs and types used throughout application
+package domain
+
+import "errors"
+
+var (
+	// `ErrPoolNotFound` returned when no liquidity pool is found for a token
+	ErrPoolNotFound = errors.New("liquidity pool not found")
+)
+
+// token safety risk flags
+const (
+	RiskMintAuthority       = "mint_authority_active"
+	RiskFreezeAuthority     = "freeze_authority_active"
+	RiskMutableMetadata     = "metadata_mutable"
+	RiskHolderConcentration = "top10_holder_concentration"
+	RiskLPUnlocked          = "lp_not_burned_or_locked"
+	RiskNoPool              = "no_liquidity_pool_found" // Raydium AMM v4 / CPMM, PumpSwap and Orca Whirlpool pools are searched
+	RiskPermanentDelegate   = "permanent_delegate"
+	RiskTransferFee         = "transfer_fee"
+	RiskTransferHook        = "transfer_hook"
+	RiskNonTransferable     = "non_transferable"
+	RiskDefaultFrozen       = "default_account_frozen"
+	RiskPaused              = "paused"
+)
+
+// LP status of a pool
+const (
+	LPStatusSecured      = "secured"      // burned or locked
+	LPStatusWithdrawable = "withdrawable" // enough LP in circulation to pull the liquidity
+	LPStatusUnknown      = "unknown"      // the venue has no LP token (i.e: concentrated liquidity positions)
+)
+
+// `TokenHolder` represents a token account holding a mint
+// ProgramOwned is set when the account's owner is a program derived address
+// i.e: a locker, escrow or pool rather than a wallet
+type TokenHolder struct {
+	Account      string  `json:"account"`
+	Owner        string  `json:"owner"`
+	Amount       float64 `json:"amount"`
+	ProgramOwned bool    `json:"program_owned"`
+}
+
+// `LiquidityPool` represents the pool backing a token's main market
+// LPReserve is the LP minted by the pool that has not been withdrawn,
+// LPSupply the LP still in circulation: the difference has been burned.
+// LPMint is empty for venues without an LP token.
+type LiquidityPool struct {
+	Address      string  `json:"address"`
+	Venue        string  `json:"venue"`
+	BaseMint     string  `json:"base_mint"`
+	QuoteMint    string  `json:"quote_mint"`
+	LPMint       string  `json:"lp_mint"`
+	BaseVault    string  `json:"base_vault"`
+	QuoteVault   string  `json:"quote_vault"`
+	LPReserve    float64 `json:"lp_reserve"`
+	LPSupply     float64 `json:"lp_supply"`
+	LiquidityUSD float64 `json:"liquidity_usd"`
+}
+
+// `PoolSafety` represents the share of a pool's LP that can no longer be withdrawn
+type PoolSafety struct {
+	Address      string  `json:"address"`
+	Venue        string  `json:"venue"`
+	LPMint       string  `json:"lp_mint,omitempty"`
+	LiquidityUSD float64 `json:"liquidity_usd"`
+	LPStatus     string  `json:"lp_status"`
+	LPBurnedPct  float64 `json:"lp_burned_pct"`
+	LPLockedPct  float64 `json:"lp_locked_pct"`
+}
+
+// `TokenSafety` represents a rug check report for a token
+// Risks lists the flags raised, an empty list meaning no risk was found
+type TokenSafety struct {
+	Address                string      `json:"token_address"`
+	MintAuthorityRevoked   bool        `json:"mint_authority_revoked"`
+	MintAuthority          string      `json:"mint_authority,omitempty"`
+	FreezeAuthorityRevoked bool        `json:"freeze_authority_revoked"`
+	FreezeAuthority        string      `json:"freeze_authority,omitempty"`
+	MetadataMutable        bool        `json:"metadata_mutable"`
+	UpdateAuthority        string      `json:"update_authority,omitempty"`
+	Top10HolderPct         float64     `json:"top10_holder_pct"`
+	Pool                   *PoolSafety `json:"pool,omitempty"`
+	Risks                  []string    `json:"risks"`
+}
@@ -1,7 +1,20 @@
 // Package `domain` contains structs and types used throughout application
 package domain
 
-import "time"
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// `ErrTokenNotFound` returned when a token is not cached in the DB,
+	// or its mint or metadata account does not exist on chain
+	ErrTokenNotFound = errors.New("token not found")
+)
+
+// `AgeSourceCreationTxn` marks a CreatedAt found from the token's earliest transaction
+// stored alongside it, rows without it hold no trusted age
+const AgeSourceCreationTxn = "creation_txn"
 
 // `Token` represents basic token information with optional JSON fields
 type Token struct {
@@ -15,12 +28,75 @@ type Token struct {
 // `TokenResponse` represents transformed token data for API responses
 // as well as DB token entries
 type TokenResponse struct {
-	Address   string    `json:"token_address"`
-	Name      string    `json:"name"`
-	Symbol    string    `json:"symbol"`
-	CreatedAt time.Time `json:"created_at"`
-	Supply    float64   `json:"supply"`
-	Price     float64   `json:"price"`
-	FDV       float64   `json:"fdv"`
-	Socials   string    `json:"socials,omitempty"`
+	Address           string           `json:"token_address"`
+	Name              string           `json:"name"`
+	Symbol            string           `json:"symbol"`
+	CreatedAt         time.Time        `json:"created_at"`
+	AgeSource         string           `json:"-"` // empty while the age is not determined, or was cached by an earlier version
+	Supply            float64          `json:"supply"`
+	CirculatingSupply float64          `json:"circulating_supply"`
+	Price             float64          `json:"price"`
+	PriceProvider     string           `json:"price_provider"`
+	PriceUpdatedAt    time.Time        `json:"price_updated_at"`
+	Quote             string           `json:"quote"`             // currency Price, MarketCap and FDV are denominated in
+	Liquidity         *float64         `json:"liquidity_usd"`     // USD held by the main pool's vaults, nil without a pool
+	MarketUpdatedAt   time.Time        `json:"market_updated_at"` // when CirculatingSupply and Liquidity were measured
+	MarketCap         float64          `json:"market_cap"`
+	FDV               float64          `json:"fdv"`
+	Program           string           `json:"program"`
+	Extensions        *TokenExtensions `json:"extensions,omitempty"`
+	OffChainMetadata
+}
+
+// `TokenMetadata` represents a token's on-chain Metaplex metadata
+// URI points to the off-chain JSON metadata, UpdateAuthority may change it while Mutable
+type TokenMetadata struct {
+	Mint            string `json:"mint"`
+	Name            string `json:"name"`
+	Symbol          string `json:"symbol"`
+	URI             string `json:"uri,omitempty"`
+	UpdateAuthority string `json:"update_authority,omitempty"`
+	Mutable         bool   `json:"mutable"`
+}
+
+// `OffChainMetadata` represents the project details of a token's off-chain JSON metadata
+// links are only kept when they are valid http(s) URLs
+type OffChainMetadata struct {
+	Image       string `json:"image,omitempty"`
+	Description string `json:"description,omitempty"`
+	Website     string `json:"website,omitempty"`
+	Twitter     string `json:"twitter,omitempty"`
+	Telegram    string `json:"telegram,omitempty"`
+}
+
+// `MintInfo` represents the state of a token's mint account
+// Extensions is only set for mints owned by the Token-2022 program
+type MintInfo struct {
+	Program         string           `json:"program"`
+	Decimals        int              `json:"decimals"`
+	MintAuthority   string           `json:"mint_authority,omitempty"`
+	FreezeAuthority string           `json:"freeze_authority,omitempty"`
+	Extensions      *TokenExtensions `json:"extensions,omitempty"`
+}
+
+// `TokenExtensions` represents the Token-2022 extensions enabled on a mint
+// Enabled lists every extension by name, notable ones are decoded into their own fields
+type TokenExtensions struct {
+	Enabled              []string     `json:"enabled"`
+	TransferFee          *TransferFee `json:"transfer_fee,omitempty"`
+	PermanentDelegate    string       `json:"permanent_delegate,omitempty"`
+	NonTransferable      bool         `json:"non_transferable"`
+	TransferHookProgram  string       `json:"transfer_hook_program,omitempty"`
+	DefaultAccountFrozen bool         `json:"default_account_frozen"`
+	MintCloseAuthority   string       `json:"mint_close_authority,omitempty"`
+	Paused               bool         `json:"paused"`
+	MetadataAddress      string       `json:"metadata_address,omitempty"`
+}
+
+// `TransferFee` represents the fee withheld on every transfer of a Token-2022 mint
+// MaximumFee is in UI units of the token
+type TransferFee struct {
+	BasisPoints uint16  `json:"basis_points"`
+	MaximumFee  float64 `json:"maximum_fee"`
+	Epoch       uint64  `json:"epoch"`
 }
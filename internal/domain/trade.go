@@ -0,0 +1,47 @@
+// Package `domain` contains structs and types used throughout application
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// `ErrInvalidCursor` returned when a pagination cursor cannot be decoded
+	ErrInvalidCursor = errors.New("invalid cursor")
+)
+
+// `Trade` represents a detected swap persisted for a wallet
+// Input is the token sent, Output the token received.
+// Priced is false when no USD value could be determined, USDValue is then 0.
+type Trade struct {
+	Signature     string    `json:"signature"`
+	WalletAddress string    `json:"wallet_address"`
+	Slot          uint64    `json:"slot"`
+	BlockTime     time.Time `json:"block_time"`
+	InputMint     string    `json:"input_mint"`
+	InputAmount   float64   `json:"input_amount"`
+	OutputMint    string    `json:"output_mint"`
+	OutputAmount  float64   `json:"output_amount"`
+	Venue         string    `json:"venue,omitempty"`
+	USDValue      float64   `json:"usd_value"`
+	Priced        bool      `json:"priced"`
+}
+
+// `TradeFilter` narrows a wallet's trade history
+// Mint matches either side of a trade, From/To bound the block time when non-zero
+type TradeFilter struct {
+	WalletAddress string
+	Mint          string
+	From          time.Time
+	To            time.Time
+	Cursor        string
+	Limit         int
+}
+
+// `TradePage` represents a page of trades, newest first
+// NextCursor is empty once the history is exhausted
+type TradePage struct {
+	Trades     []Trade `json:"trades"`
+	NextCursor string  `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,70 @@
+// Package `domain` contains structs and types used throughout application
+package domain
+
+// `TxnType` labels what a txn did to a tracked wallet
+type TxnType string
+
+const (
+	TxnTransferIn  TxnType = "transfer_in"
+	TxnTransferOut TxnType = "transfer_out"
+	TxnSwap        TxnType = "swap"
+	TxnMint        TxnType = "mint"
+	TxnBurn        TxnType = "burn"
+	TxnLPAdd       TxnType = "lp_add"
+	TxnLPRemove    TxnType = "lp_remove"
+	TxnNFTBuy      TxnType = "nft_buy"
+	TxnNFTSell     TxnType = "nft_sell"
+	TxnStake       TxnType = "stake"
+	TxnUnknown     TxnType = "unknown"
+)
+
+// `TxnEvent` represents a classified txn touching a tracked wallet
+// only the payload matching Type is set
+type TxnEvent struct {
+	Type      TxnType           `json:"type"`
+	Transfer  *TransferPayload  `json:"transfer,omitempty"`
+	Swap      *SwapResult       `json:"swap,omitempty"`
+	Mint      *TokenAmount      `json:"mint,omitempty"`
+	Burn      *TokenAmount      `json:"burn,omitempty"`
+	Liquidity *LiquidityPayload `json:"liquidity,omitempty"`
+	NFT       *NFTPayload       `json:"nft,omitempty"`
+	Stake     *StakePayload     `json:"stake,omitempty"`
+}
+
+// `TokenAmount` represents an amount of a single token
+// native SOL is reported under the wSOL mint
+type TokenAmount struct {
+	Address string  `json:"address"`
+	Symbol  string  `json:"symbol"`
+	Amount  float64 `json:"amount"`
+}
+
+// `TransferPayload` represents SOL or SPL moved in or out of a tracked wallet
+// Counterparty is the other wallet involved, when it can be determined
+type TransferPayload struct {
+	TokenAmount
+	Counterparty string `json:"counterparty,omitempty"`
+}
+
+// `LiquidityPayload` represents tokens deposited to, or withdrawn from, a liquidity pool
+// LP holds the LP token (or position NFT) minted or burned in exchange
+type LiquidityPayload struct {
+	Venue  string        `json:"venue,omitempty"`
+	Tokens []TokenAmount `json:"tokens"`
+	LP     *TokenAmount  `json:"lp,omitempty"`
+}
+
+// `NFTPayload` represents an NFT bought or sold by a tracked wallet
+type NFTPayload struct {
+	Address     string  `json:"address"`
+	Price       float64 `json:"price"` // in SOL
+	Marketplace string  `json:"marketplace,omitempty"`
+}
+
+// `StakePayload` represents SOL staked or unstaked by a tracked wallet
+// Amount is negative when SOL is staked, positive when withdrawn
+type StakePayload struct {
+	Program string       `json:"program"`
+	Amount  float64      `json:"amount"`
+	Token   *TokenAmount `json:"token,omitempty"` // liquid staking token minted or burned
+}
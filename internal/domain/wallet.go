@@ -0,0 +1,34 @@
+// Package `domain` contains structs and types used throughout application
+package domain
+
+import "errors"
+
+var (
+	// `ErrInvalidAddress` returned when an address is not a valid base58 Solana public key
+	ErrInvalidAddress = errors.New("invalid solana address")
+)
+
+// `TokenHolding` represents a wallet's balance of a single SPL or Token-2022 mint
+// aggregated across every token account the wallet owns for that mint.
+// Price and Value are denominated in the Portfolio's Quote
+type TokenHolding struct {
+	Mint     string  `json:"mint"`
+	Name     string  `json:"name,omitempty"`
+	Symbol   string  `json:"symbol,omitempty"`
+	Program  string  `json:"program"`
+	Amount   float64 `json:"amount"`
+	Decimals int     `json:"decimals"`
+	Price    float64 `json:"price"`
+	Value    float64 `json:"value"`
+}
+
+// `Portfolio` represents a wallet's SOL balance and token holdings
+// prices and values are denominated in Quote
+type Portfolio struct {
+	WalletAddress string         `json:"wallet_address"`
+	Quote         string         `json:"quote"`
+	SOLBalance    float64        `json:"sol_balance"`
+	SOLValue      float64        `json:"sol_value"`
+	Holdings      []TokenHolding `json:"holdings"`
+	TotalValue    float64        `json:"total_value"`
+}
@@ -3,8 +3,12 @@ package handler
 
 import (
 	"encoding/json"
+	"fmt"
 	"log"
 	"net/http"
+	"slices"
+	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/jakobsym/aura/internal/domain"
@@ -59,7 +63,7 @@ func (ah *AccountHandler) CreateUserEntry(w http.ResponseWriter, r *http.Request
 	json.NewEncoder(w).Encode("success")
 }
 
-// TODO: `UntrackWallet() not implemented`
+// `UntrackWallet` handles PUT requests to stop tracking a wallet
 func (ah *AccountHandler) UntrackWallet(w http.ResponseWriter, r *http.Request) {
 	walletAddress := chi.URLParam(r, "wallet_address")
 	if walletAddress == "" {
@@ -73,9 +77,63 @@ func (ah *AccountHandler) UntrackWallet(w http.ResponseWriter, r *http.Request)
 		return
 	}
 	if err := ah.as.UntrackWallet(walletAddress, user.TelegramId); err != nil {
+		log.Printf("failed to untrack wallet: %v", err)
 		http.Error(w, "failed to untrack wallet", http.StatusInternalServerError)
 		return
 	}
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode("success")
 }
+
+// interval between keep-alive comments on an idle notification stream
+const streamKeepAlive = 30 * time.Second
+
+// `StreamNotifications` handles GET requests for a server-sent event stream of wallet notifications
+// streaming only the notifications addressed to the user given by the required ?telegram_id=.
+// each event carries the wallet, the decoded txn and the user as its only recipient
+func (ah *AccountHandler) StreamNotifications(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	telegramId, err := strconv.Atoi(r.URL.Query().Get("telegram_id"))
+	if err != nil {
+		http.Error(w, "must provide an integer telegram_id", http.StatusBadRequest)
+		return
+	}
+
+	notifications := ah.as.ListenNotifications()
+	defer ah.as.StopListenNotifications(notifications)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(streamKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case notification := <-notifications:
+			if !slices.Contains(notification.Recipients, telegramId) {
+				continue
+			}
+			// other users tracking the wallet are not disclosed
+			notification.Recipients = []int{telegramId}
+			data, err := json.Marshal(notification)
+			if err != nil {
+				log.Printf("failed to encode notification: %v", err)
+				continue
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", notification.Event.Type, data)
+			flusher.Flush()
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
@@ -3,9 +3,12 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/jakobsym/aura/internal/domain"
 	"github.com/jakobsym/aura/internal/service"
 )
 
@@ -20,14 +23,40 @@ func NewTokenHandler(s *service.TokenService) *TokenHandler {
 }
 
 // `GetTokenDetails` handles GET requests for token information
+// supports ?refresh=true to bypass cached token data and ?quote=USD|SOL|USDC|EUR
 func (th *TokenHandler) GetTokenDetails(w http.ResponseWriter, r *http.Request) {
 	tokenAddress := chi.URLParam(r, "token_address")
 	if tokenAddress == "" {
 		http.Error(w, "must provide valid token address", http.StatusBadRequest)
 		return
 	}
-	res, err := th.s.GetTokenData(r.Context(), tokenAddress)
+	refresh, _ := strconv.ParseBool(r.URL.Query().Get("refresh"))
+	res, err := th.s.GetTokenData(r.Context(), tokenAddress, refresh, r.URL.Query().Get("quote"))
 	if err != nil {
+		if errors.Is(err, domain.ErrInvalidQuote) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
+
+// `GetTokenSafety` handles GET requests for a token's rug check report
+func (th *TokenHandler) GetTokenSafety(w http.ResponseWriter, r *http.Request) {
+	tokenAddress := chi.URLParam(r, "token_address")
+	if tokenAddress == "" {
+		http.Error(w, "must provide valid token address", http.StatusBadRequest)
+		return
+	}
+	res, err := th.s.GetTokenSafety(r.Context(), tokenAddress)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidAddress) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
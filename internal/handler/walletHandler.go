@@ -0,0 +1,121 @@
+// Package `handler` implements HTTP request handlers that connect with API endpoints
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jakobsym/aura/internal/domain"
+	"github.com/jakobsym/aura/internal/service"
+)
+
+// `WalletHandler` handles HTTP requests for wallet activity related business logic
+type WalletHandler struct {
+	ws *service.WalletService
+}
+
+// `NewWalletHandler` creates a new WalletHandler instance with dependency injection
+func NewWalletHandler(ws *service.WalletService) *WalletHandler {
+	return &WalletHandler{ws: ws}
+}
+
+// `GetWalletTrades` handles GET requests for a wallet's trade history
+// supports ?cursor=, ?limit=, ?mint= and ?from= / ?to= (RFC3339) query params
+func (wh *WalletHandler) GetWalletTrades(w http.ResponseWriter, r *http.Request) {
+	walletAddress := chi.URLParam(r, "wallet_address")
+	if walletAddress == "" {
+		http.Error(w, "must provide valid wallet address", http.StatusBadRequest)
+		return
+	}
+	query := r.URL.Query()
+	filter := domain.TradeFilter{
+		WalletAddress: walletAddress,
+		Mint:          query.Get("mint"),
+		Cursor:        query.Get("cursor"),
+	}
+
+	var err error
+	if limit := query.Get("limit"); limit != "" {
+		if filter.Limit, err = strconv.Atoi(limit); err != nil {
+			http.Error(w, "limit must be an integer", http.StatusBadRequest)
+			return
+		}
+	}
+	if from := query.Get("from"); from != "" {
+		if filter.From, err = time.Parse(time.RFC3339, from); err != nil {
+			http.Error(w, "from must be an RFC3339 timestamp", http.StatusBadRequest)
+			return
+		}
+	}
+	if to := query.Get("to"); to != "" {
+		if filter.To, err = time.Parse(time.RFC3339, to); err != nil {
+			http.Error(w, "to must be an RFC3339 timestamp", http.StatusBadRequest)
+			return
+		}
+	}
+
+	res, err := wh.ws.GetTrades(r.Context(), filter)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidCursor) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		log.Printf("failed to fetch trades: %v", err)
+		http.Error(w, "error fetching trades", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
+
+// `GetWalletPnL` handles GET requests for a wallet's positions and PnL
+// supports ?method=fifo|average and ?quote=USD query params
+func (wh *WalletHandler) GetWalletPnL(w http.ResponseWriter, r *http.Request) {
+	walletAddress := chi.URLParam(r, "wallet_address")
+	if walletAddress == "" {
+		http.Error(w, "must provide valid wallet address", http.StatusBadRequest)
+		return
+	}
+	query := r.URL.Query()
+	res, err := wh.ws.GetPnL(r.Context(), walletAddress, query.Get("method"), query.Get("quote"))
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidPnLMethod) || errors.Is(err, domain.ErrInvalidQuote) || errors.Is(err, domain.ErrInvalidPnLQuote) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		log.Printf("failed to compute pnl: %v", err)
+		http.Error(w, "error computing pnl", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
+
+// `GetWalletPortfolio` handles GET requests for a wallet's balances and their value
+// supports ?quote=USD|SOL|USDC|EUR and ?include_unpriced=true query params
+func (wh *WalletHandler) GetWalletPortfolio(w http.ResponseWriter, r *http.Request) {
+	walletAddress := chi.URLParam(r, "wallet_address")
+	if walletAddress == "" {
+		http.Error(w, "must provide valid wallet address", http.StatusBadRequest)
+		return
+	}
+	query := r.URL.Query()
+	includeUnpriced, _ := strconv.ParseBool(query.Get("include_unpriced"))
+	res, err := wh.ws.GetPortfolio(r.Context(), walletAddress, query.Get("quote"), includeUnpriced)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidAddress) || errors.Is(err, domain.ErrInvalidQuote) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		log.Printf("failed to fetch portfolio: %v", err)
+		http.Error(w, "error fetching portfolio", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
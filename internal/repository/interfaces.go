@@ -8,11 +8,16 @@ import (
 	"github.com/jakobsym/aura/internal/domain"
 )
 
+// `PostgresTokenRepo` defines operations for caching token data within a PostgreSQL database.
 type PostgresTokenRepo interface {
 	// `DeleteToken` deletes a token entry from DB based on given tokenAddress
 	DeleteToken(tokenAddress string) error
-	// `CreateToken` creates token entry within DB after transforming token data
-	CreateToken(token domain.TokenResponse) error
+	// `GetToken` retrieves the cached token entry for a given tokenAddress
+	GetToken(tokenAddress string) (domain.TokenResponse, error)
+	// `UpsertToken` creates a token entry within DB, or refreshes the existing one
+	UpsertToken(token domain.TokenResponse) error
+	// `UpdateTokenAge` stores the creation time of a stored token, reporting whether it is stored
+	UpdateTokenAge(tokenAddress string, createdAt time.Time) (bool, error)
 }
 
 // `SolanaTokenRepo` defines operations for extracting token related data via RPC nodes.
@@ -20,29 +25,53 @@ type SolanaTokenRepo interface {
 	// `GetTokenAge` retrieves the time of creation for a given tokenAddress
 	GetTokenAge(ctx context.Context, tokenAddress string) (time.Time, error) // RPC
 
-	// `GetTokenNameAndSymbol` retrieves the name and symbol for a given tokenAddress
-	// returning as a slice [name, symbol]
-	GetTokenNameAndSymbol(ctx context.Context, tokenAddress string) ([]string, error) // RPC
+	// `GetTokenMetadata` retrieves the name, symbol and metadata URI for a given tokenAddress
+	GetTokenMetadata(ctx context.Context, tokenAddress string) (domain.TokenMetadata, error) // RPC
+
+	// `GetMintInfo` retrieves the owning program, authorities and Token-2022 extensions of a given tokenAddress
+	GetMintInfo(ctx context.Context, tokenAddress string) (domain.MintInfo, error) // RPC
+
+	// `GetLargestHolders` retrieves the largest token accounts of a given tokenAddress and their owners
+	GetLargestHolders(ctx context.Context, tokenAddress string) ([]domain.TokenHolder, error) // RPC
+
+	// `GetHeldBalance` sums the balance of a given tokenAddress held by addresses
+	// each address being either a token account of the mint or an owner of token accounts
+	GetHeldBalance(ctx context.Context, tokenAddress string, addresses []string) (float64, error) // RPC
+
+	// `GetLiquidityPool` retrieves the main liquidity pool of a given tokenAddress
+	GetLiquidityPool(ctx context.Context, tokenAddress string) (domain.LiquidityPool, error) // RPC
+
+	// `GetOffChainMetadata` retrieves the off-chain JSON metadata a token's metadata URI points to
+	GetOffChainMetadata(ctx context.Context, uri string) (domain.OffChainMetadata, error) // IPFS / Arweave / HTTP
 
 	// `GetTokenSupply` retrieves the total token supply for a given tokenAddress
 	GetTokenSupply(ctx context.Context, tokenAddress string) (float64, error) // RPC
 
-	// `GetTokenPrice`retrieves the token price for a given tokenAddress
-	GetTokenPrice(ctx context.Context, tokenAddress string) (float64, error) // Jupiter
+	// `GetTokenPrice` retrieves the USD token price for a given tokenAddress
+	GetTokenPrice(ctx context.Context, tokenAddress string) (domain.TokenPrice, error) // PriceProvider
 
-	// `GetTokenFDV` retrieves the Fully Diluted Value (FDV) for a given tokenAddress
+	// `GetTokenFDV` retrieves the Fully Diluted Value (FDV), price times total supply
 	GetTokenFDV(ctx context.Context, price float64, supply float64) float64
 }
 
+// `SolanaWalletRepo` defines operations for extracting wallet balances via RPC nodes.
+type SolanaWalletRepo interface {
+	// `GetSolBalance` retrieves the native SOL balance of a given walletAddress
+	GetSolBalance(ctx context.Context, walletAddress string) (float64, error) // RPC
+
+	// `GetTokenHoldings` retrieves the SPL Token and Token-2022 balances of a given walletAddress
+	GetTokenHoldings(ctx context.Context, walletAddress string) ([]domain.TokenHolding, error) // RPC
+}
+
 // `SolanaWebSocketRepo` defines websocket based operations for extracting real-time transaction data
 // via Helius Websocket RPC.
 type SolanaWebSocketRepo interface {
 	// `AccountListen` starts listening for updates across the websocket connection and returns a channel
-	// that recieves a HeliusLogResponse
-	AccountListen(ctx context.Context) (<-chan domain.HeliusLogResponse, error)
+	// that recieves a WalletNotification for each decoded wallet event
+	AccountListen(ctx context.Context) (<-chan domain.WalletNotification, error)
 
 	// `StopAccountListen` terminates AccountListen process
-	StopAccountListen(<-chan domain.HeliusLogResponse)
+	StopAccountListen(<-chan domain.WalletNotification)
 
 	// `LogsSubscribe` subscribe to transaction logs for a given walletAddress
 	LogsSubscribe(ctx context.Context, walletAddress string, userId int) error
@@ -50,8 +79,9 @@ type SolanaWebSocketRepo interface {
 	// `AccountSubscribe` subscribes to an Account for a given walletAddress
 	AccountSubscribe(ctx context.Context, walletAddress string, userId int) error
 
-	// `AccountUnsubscribe` terminates AccountSubscribe process
-	AccountUnsubscribe(ctx context.Context, walletAddress string, userId int) (bool, error)
+	// `LogsUnsubscribe` stops userId tracking walletAddress
+	// terminating the wallet's log subscription when stillTracked reports no other user tracks it
+	LogsUnsubscribe(ctx context.Context, walletAddress string, userId int, stillTracked bool) error
 
 	// `HandleWebSocketConnection` mangages Websocket connection
 	HandleWebSocketConnection(ctx context.Context)
@@ -62,8 +92,16 @@ type SolanaWebSocketRepo interface {
 	// `GetTxnData` retrieves transaction details for a given transaction signature
 	GetTxnData(signature string) (domain.TransactionResult, error)
 
-	// `GetTxnSwapData` extracts swap information from a TransactionResult
-	GetTxnSwapData(payload domain.TransactionResult) ([]domain.SwapResult, error)
+	// `GetSignaturesForAddress` retrieves up to limit txn signatures involving an address, newest first
+	// paging backwards from the before signature when given
+	GetSignaturesForAddress(address, before string, limit int) ([]domain.SignatureInfo, error)
+
+	// `GetTxnSwapData` extracts swap information from a TransactionResult for a given walletAddress
+	GetTxnSwapData(payload domain.TransactionResult, walletAddress string) ([]domain.SwapResult, error)
+
+	// `ClassifyTxn` labels what a TransactionResult did to a given walletAddress
+	// i.e: transfer, swap, mint, burn, LP add/remove, NFT buy/sell, stake
+	ClassifyTxn(payload domain.TransactionResult, walletAddress string) (domain.TxnEvent, error)
 }
 
 // `AccountRepo` defines operations for managing user, wallet, and subscriptions
@@ -81,6 +119,9 @@ type AccountRepo interface {
 	// `RemoveSubscription` removes a subscription entry based on the given walletAddress and userId
 	RemoveSubscription(walletAddress string, userId int) (bool, error)
 
+	// `GetSubscribers` fetches the userIds currently subscribed to a given walletAddress
+	GetSubscribers(walletAddress string) ([]int, error)
+
 	// `CreateUser` creates a new user entry based on telegramId
 	CreateUser(telegramId int) error
 
@@ -92,4 +133,58 @@ type AccountRepo interface {
 
 	// `SetWalletActive` marks a given `walletId` as active in the database
 	SetWalletActive(walletId int) error
+
+	// `GetActiveSubscriptions` fetches every active wallet along with its subscribed userIds
+	GetActiveSubscriptions() ([]domain.WalletSubscription, error)
+
+	// `GetWalletSubscribers` fetches the telegramIds of users subscribed to a given walletAddress
+	GetWalletSubscribers(walletAddress string) ([]int, error)
+}
+
+// `TradeRepo` defines operations for persisting and querying detected trades
+// within a PostgreSQL database.
+type TradeRepo interface {
+	// `CreateTrade` stores a trade, ignoring trades already stored for the same signature and wallet
+	CreateTrade(trade domain.Trade) error
+
+	// `GetTrades` fetches a page of trades for a wallet matching the given filter, newest first
+	GetTrades(filter domain.TradeFilter) (domain.TradePage, error)
+
+	// `GetTradeHistory` fetches every trade for a wallet, oldest first
+	GetTradeHistory(walletAddress string) ([]domain.Trade, error)
+}
+
+// `BackfillRepo` defines operations for tracking the progress of historical
+// wallet backfill jobs within a PostgreSQL database.
+type BackfillRepo interface {
+	// `CreateBackfillJob` stores a new job, returning false if the wallet already has one
+	CreateBackfillJob(job domain.BackfillJob) (bool, error)
+
+	// `UpdateBackfillJob` persists a job's cursor, progress and status
+	UpdateBackfillJob(job domain.BackfillJob) error
+
+	// `GetRunningBackfillJobs` fetches every job that has not finished
+	GetRunningBackfillJobs() ([]domain.BackfillJob, error)
+}
+
+// `PriceProvider` defines a source of token prices (i.e: Jupiter, DexScreener)
+type PriceProvider interface {
+	// `Name` identifies the provider a price was quoted by
+	Name() string
+
+	// `GetPrice` retrieves the USD price of a given tokenAddress
+	// returning domain.ErrPriceNotFound when the provider has no price for it
+	GetPrice(ctx context.Context, tokenAddress string) (domain.TokenPrice, error)
+}
+
+// `FXProvider` defines a source of fiat exchange rates
+type FXProvider interface {
+	// `GetRate` retrieves the USD value of one unit of a fiat currency (i.e: EUR)
+	GetRate(ctx context.Context, currency string) (float64, error)
+}
+
+// `SOLPriceHistory` defines a source of historical SOL prices
+type SOLPriceHistory interface {
+	// `GetSOLPriceAt` retrieves the USD price of SOL at a past point in time
+	GetSOLPriceAt(ctx context.Context, at time.Time) (float64, error)
 }
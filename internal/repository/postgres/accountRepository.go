@@ -9,6 +9,7 @@ import (
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jakobsym/aura/internal/domain"
 	"github.com/jakobsym/aura/internal/repository"
 )
 
@@ -45,7 +46,8 @@ func (ar *postgresAccountRepo) CheckSubscription(walletId int) (bool, error) {
 
 // `CreateSubsciption` adds a new subscription record creating a (user - wallet) connection
 func (ar *postgresAccountRepo) CreateSubscription(walletAddress string, userId, walletId int) error {
-	query := `INSERT into subscriptions(user_id, wallet_id, wallet_address) VALUES ($1, $2, $3);`
+	query := `INSERT into subscriptions(user_id, wallet_id, wallet_address) VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, wallet_id) DO NOTHING;`
 	_, err := ar.db.Exec(context.TODO(), query, userId, walletId, walletAddress)
 	if err != nil {
 		return fmt.Errorf("error inserting into join table: %v", err)
@@ -78,7 +80,7 @@ func (ar *postgresAccountRepo) RemoveSubscription(walletAddress string, userId i
 
 	// set inactive if no-one is tracking
 	if userCount == 0 {
-		_, err = tx.Exec(context.TODO(), `UPDATE wallet SET subcription_active = FALSE WHERE wallet_address=$1;`, walletAddress)
+		_, err = tx.Exec(context.TODO(), `UPDATE wallets SET subscription_active = FALSE WHERE wallet_address=$1;`, walletAddress)
 		if err != nil {
 			return false, fmt.Errorf("failed to perform operation: %w", err)
 		}
@@ -160,3 +162,80 @@ func (ar *postgresAccountRepo) GetUserID(telegramId int) (int, error) {
 	}
 	return userId, nil
 }
+
+// `GetSubscribers` fetches the userIds subscribed to a given walletAddress
+func (ar *postgresAccountRepo) GetSubscribers(walletAddress string) ([]int, error) {
+	rows, err := ar.db.Query(context.TODO(), `SELECT user_id FROM subscriptions WHERE wallet_address = $1;`, walletAddress)
+	if err != nil {
+		return nil, fmt.Errorf("error querying subscribers: %w", err)
+	}
+	userIds, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return nil, fmt.Errorf("error scanning subscribers: %w", err)
+	}
+	return userIds, nil
+}
+
+// `GetActiveSubscriptions` fetches all wallets marked `subscription_active`
+// grouped with the userIds subscribed to each wallet
+func (ar *postgresAccountRepo) GetActiveSubscriptions() ([]domain.WalletSubscription, error) {
+	query := `SELECT w.wallet_address, s.user_id FROM wallets w
+		JOIN subscriptions s ON s.wallet_id = w.id
+		WHERE w.subscription_active = TRUE
+		ORDER BY w.id;`
+	rows, err := ar.db.Query(context.TODO(), query)
+	if err != nil {
+		return nil, fmt.Errorf("error querying active subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	var subscriptions []domain.WalletSubscription
+	for rows.Next() {
+		var (
+			walletAddress string
+			userId        int
+		)
+		if err := rows.Scan(&walletAddress, &userId); err != nil {
+			return nil, fmt.Errorf("error scanning active subscriptions: %w", err)
+		}
+		// rows are ordered by wallet so consecutive rows share an entry
+		if n := len(subscriptions); n > 0 && subscriptions[n-1].WalletAddress == walletAddress {
+			subscriptions[n-1].UserIds = append(subscriptions[n-1].UserIds, userId)
+			continue
+		}
+		subscriptions = append(subscriptions, domain.WalletSubscription{
+			WalletAddress: walletAddress,
+			UserIds:       []int{userId},
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading active subscriptions: %w", err)
+	}
+	return subscriptions, nil
+}
+
+// `GetWalletSubscribers` fetches the telegramIds of every user subscribed to a given walletAddress
+func (ar *postgresAccountRepo) GetWalletSubscribers(walletAddress string) ([]int, error) {
+	query := `SELECT u.telegram_id FROM subscriptions s
+		JOIN users u ON u.id = s.user_id
+		JOIN wallets w ON w.id = s.wallet_id
+		WHERE w.wallet_address = $1;`
+	rows, err := ar.db.Query(context.TODO(), query, walletAddress)
+	if err != nil {
+		return nil, fmt.Errorf("error querying wallet subscribers: %w", err)
+	}
+	defer rows.Close()
+
+	var telegramIds []int
+	for rows.Next() {
+		var telegramId int
+		if err := rows.Scan(&telegramId); err != nil {
+			return nil, fmt.Errorf("error scanning wallet subscribers: %w", err)
+		}
+		telegramIds = append(telegramIds, telegramId)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading wallet subscribers: %w", err)
+	}
+	return telegramIds, nil
+}
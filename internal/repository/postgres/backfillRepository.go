@@ -0,0 +1,81 @@
+// Package `postgres` provides implementations of respository interfaces using PostgreSQL.
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jakobsym/aura/internal/domain"
+	"github.com/jakobsym/aura/internal/repository"
+)
+
+// `postgresBackfillRepo` implements the repository.BackfillRepo interface using PostgreSQL
+type postgresBackfillRepo struct {
+	db *pgxpool.Pool
+}
+
+// `NewPostgresBackfillRepo` creates and returns a new PostgreSQL implementation
+// of the BackfillRepo interface.
+func NewPostgresBackfillRepo(db *pgxpool.Pool) repository.BackfillRepo {
+	return &postgresBackfillRepo{db: db}
+}
+
+// `CreateBackfillJob` creates a backfill job record based on given domain.BackfillJob
+// returns false, leaving the existing record untouched, if the wallet already has a job
+func (br *postgresBackfillRepo) CreateBackfillJob(job domain.BackfillJob) (bool, error) {
+	query := `INSERT INTO backfill_jobs(wallet_address, status, before_signature, processed, max_txns, cutoff)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	ON CONFLICT (wallet_address) DO NOTHING;`
+	tag, err := br.db.Exec(context.TODO(), query,
+		job.WalletAddress,
+		job.Status,
+		job.Before,
+		job.Processed,
+		job.MaxTxns,
+		job.Cutoff,
+	)
+	if err != nil {
+		return false, fmt.Errorf("error inserting into backfill_jobs: %w", err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// `UpdateBackfillJob` persists the cursor, progress, skipped txns and status of a backfill job
+func (br *postgresBackfillRepo) UpdateBackfillJob(job domain.BackfillJob) error {
+	query := `UPDATE backfill_jobs
+	SET status = $2, before_signature = $3, processed = $4, failed_signatures = $5, updated_at = CURRENT_TIMESTAMP
+	WHERE wallet_address = $1;`
+	_, err := br.db.Exec(context.TODO(), query, job.WalletAddress, job.Status, job.Before, job.Processed, job.Failed)
+	if err != nil {
+		return fmt.Errorf("error updating backfill_jobs: %w", err)
+	}
+	return nil
+}
+
+// `GetRunningBackfillJobs` fetches every backfill job that has not finished, oldest first
+func (br *postgresBackfillRepo) GetRunningBackfillJobs() ([]domain.BackfillJob, error) {
+	query := `SELECT wallet_address, status, COALESCE(before_signature, ''), processed, max_txns, cutoff,
+	COALESCE(failed_signatures, '{}')
+	FROM backfill_jobs
+	WHERE status = $1
+	ORDER BY created_at;`
+	rows, err := br.db.Query(context.TODO(), query, domain.BackfillRunning)
+	if err != nil {
+		return nil, fmt.Errorf("error querying backfill_jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []domain.BackfillJob
+	for rows.Next() {
+		var job domain.BackfillJob
+		if err := rows.Scan(&job.WalletAddress, &job.Status, &job.Before, &job.Processed, &job.MaxTxns, &job.Cutoff, &job.Failed); err != nil {
+			return nil, fmt.Errorf("error scanning backfill_jobs: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating backfill_jobs: %w", err)
+	}
+	return jobs, nil
+}
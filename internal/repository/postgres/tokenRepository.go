@@ -66,19 +66,154 @@ func (tr *postgresTokenRepo) DeleteToken(tokenAddress string) error {
 	return nil
 }
 
-// `CreateToken` creates a token record based on given domain.TokenResponse
-func (tr *postgresTokenRepo) CreateToken(token domain.TokenResponse) error {
+// `GetToken` retrieves the token record for a given tokenAddress
+// market cap and FDV are not stored and left for the caller to derive.
+// created_at is only read when its age_source shows it was found from the token's creation txn
+func (tr *postgresTokenRepo) GetToken(tokenAddress string) (domain.TokenResponse, error) {
+	query := `SELECT token_address, token_name, token_symbol, token_supply,
+		COALESCE(circulating_supply, token_supply),
+		CASE WHEN age_source = 'creation_txn' THEN created_at END, COALESCE(age_source, ''),
+		COALESCE(token_image, ''), COALESCE(token_description, ''), COALESCE(token_website, ''),
+		COALESCE(token_twitter, ''), COALESCE(token_telegram, ''),
+		COALESCE(token_program, ''), token_extensions,
+		COALESCE(token_price, 0), COALESCE(price_provider, ''), price_updated_at, liquidity_usd, market_updated_at
+		FROM tokens WHERE token_address = $1`
+	var (
+		token           domain.TokenResponse
+		createdAt       *time.Time
+		priceUpdatedAt  *time.Time
+		marketUpdatedAt *time.Time
+	)
+	err := tr.db.QueryRow(context.TODO(), query, tokenAddress).Scan(
+		&token.Address,
+		&token.Name,
+		&token.Symbol,
+		&token.Supply,
+		&token.CirculatingSupply,
+		&createdAt,
+		&token.AgeSource,
+		&token.Image,
+		&token.Description,
+		&token.Website,
+		&token.Twitter,
+		&token.Telegram,
+		&token.Program,
+		&token.Extensions,
+		&token.Price,
+		&token.PriceProvider,
+		&priceUpdatedAt,
+		&token.Liquidity,
+		&marketUpdatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return domain.TokenResponse{}, fmt.Errorf("%w: %v", domain.ErrTokenNotFound, err)
+		}
+		return domain.TokenResponse{}, fmt.Errorf("error querying tokens: %w", err)
+	}
+	if createdAt != nil {
+		token.CreatedAt = createdAt.UTC()
+	}
+	if priceUpdatedAt != nil {
+		token.PriceUpdatedAt = priceUpdatedAt.UTC()
+	}
+	if marketUpdatedAt != nil {
+		token.MarketUpdatedAt = marketUpdatedAt.UTC()
+	}
+	return token, nil
+}
+
+// `UpdateTokenAge` sets the creation time of a token found from its creation txn
+// Returns false if the token is not stored.
+func (tr *postgresTokenRepo) UpdateTokenAge(tokenAddress string, createdAt time.Time) (bool, error) {
+	query := `UPDATE tokens SET created_at = $2, age_source = 'creation_txn' WHERE token_address = $1;`
+	result, err := tr.db.Exec(context.TODO(), query, tokenAddress, createdAt)
+	if err != nil {
+		return false, fmt.Errorf("error updating token age: %w", err)
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+// `UpsertToken` creates a token record based on given domain.TokenResponse
+// refreshing the record in place if the token is already stored.
+// A stored program and extensions are kept when the token comes without a program (mint lookup failed),
+// and a creation time found from the token's creation txn is kept once stored
+func (tr *postgresTokenRepo) UpsertToken(token domain.TokenResponse) error {
 	query := `INSERT INTO tokens(
 		token_address,
-		toke_name,
+		token_name,
 		token_symbol,
 		token_supply,
+		circulating_supply,
 		created_at,
-		token_social
-	) VALUES ($1, $2, $3, $4, $5, $6);`
-	err := tr.db.QueryRow(context.TODO(), query, token.Address, token.Name, token.Symbol, token.Supply, token.CreatedAt, token.Socials)
+		age_source,
+		token_image,
+		token_description,
+		token_website,
+		token_twitter,
+		token_telegram,
+		token_program,
+		token_extensions,
+		token_price,
+		price_provider,
+		price_updated_at,
+		liquidity_usd,
+		market_updated_at
+	) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+	ON CONFLICT (token_address) DO UPDATE SET
+		token_name = EXCLUDED.token_name,
+		token_symbol = EXCLUDED.token_symbol,
+		token_supply = EXCLUDED.token_supply,
+		circulating_supply = EXCLUDED.circulating_supply,
+		created_at = CASE WHEN tokens.age_source = 'creation_txn' THEN tokens.created_at ELSE EXCLUDED.created_at END,
+		age_source = CASE WHEN tokens.age_source = 'creation_txn' THEN tokens.age_source ELSE EXCLUDED.age_source END,
+		token_image = EXCLUDED.token_image,
+		token_description = EXCLUDED.token_description,
+		token_website = EXCLUDED.token_website,
+		token_twitter = EXCLUDED.token_twitter,
+		token_telegram = EXCLUDED.token_telegram,
+		token_program = COALESCE(NULLIF(EXCLUDED.token_program, ''), tokens.token_program),
+		token_extensions = CASE WHEN EXCLUDED.token_program = '' THEN tokens.token_extensions ELSE EXCLUDED.token_extensions END,
+		token_price = EXCLUDED.token_price,
+		price_provider = EXCLUDED.price_provider,
+		price_updated_at = EXCLUDED.price_updated_at,
+		liquidity_usd = EXCLUDED.liquidity_usd,
+		market_updated_at = EXCLUDED.market_updated_at,
+		updated_at = CURRENT_TIMESTAMP;`
+	// an unknown age is stored as NULL rather than a zero timestamp
+	var (
+		createdAt *time.Time
+		ageSource *string
+	)
+	if !token.CreatedAt.IsZero() {
+		createdAt = &token.CreatedAt
+	}
+	if token.AgeSource != "" {
+		ageSource = &token.AgeSource
+	}
+	_, err := tr.db.Exec(context.TODO(), query,
+		token.Address,
+		token.Name,
+		token.Symbol,
+		token.Supply,
+		token.CirculatingSupply,
+		createdAt,
+		ageSource,
+		token.Image,
+		token.Description,
+		token.Website,
+		token.Twitter,
+		token.Telegram,
+		token.Program,
+		token.Extensions,
+		token.Price,
+		token.PriceProvider,
+		token.PriceUpdatedAt,
+		token.Liquidity,
+		token.MarketUpdatedAt,
+	)
 	if err != nil {
-		return fmt.Errorf("error inserting into tokens: %v", err)
+		return fmt.Errorf("error upserting into tokens: %w", err)
 	}
 	return nil
 }
@@ -0,0 +1,184 @@
+// Package `postgres` provides implementations of respository interfaces using PostgreSQL.
+package postgres
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jakobsym/aura/internal/domain"
+	"github.com/jakobsym/aura/internal/repository"
+)
+
+// `postgresTradeRepo` implements the repository.TradeRepo interface using PostgreSQL
+type postgresTradeRepo struct {
+	db *pgxpool.Pool
+}
+
+// `NewPostgresTradeRepo` creates and returns a new PostgreSQL implementation
+// of the TradeRepo interface.
+func NewPostgresTradeRepo(db *pgxpool.Pool) repository.TradeRepo {
+	return &postgresTradeRepo{db: db}
+}
+
+// `CreateTrade` creates a trade record based on given domain.Trade
+// trades already stored for the same (signature, wallet_address) are left untouched
+func (tr *postgresTradeRepo) CreateTrade(trade domain.Trade) error {
+	query := `INSERT INTO trades(
+		signature,
+		wallet_address,
+		slot,
+		block_time,
+		input_mint,
+		input_amount,
+		output_mint,
+		output_amount,
+		venue,
+		usd_value
+	) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	ON CONFLICT (signature, wallet_address) DO NOTHING;`
+	// unpriced trades are stored with a NULL usd_value
+	var usdValue *float64
+	if trade.Priced {
+		usdValue = &trade.USDValue
+	}
+	_, err := tr.db.Exec(context.TODO(), query,
+		trade.Signature,
+		trade.WalletAddress,
+		trade.Slot,
+		trade.BlockTime,
+		trade.InputMint,
+		trade.InputAmount,
+		trade.OutputMint,
+		trade.OutputAmount,
+		trade.Venue,
+		usdValue,
+	)
+	if err != nil {
+		return fmt.Errorf("error inserting into trades: %w", err)
+	}
+	return nil
+}
+
+// `GetTrades` fetches a page of trades for filter.WalletAddress ordered by slot, newest first
+// Pagination is keyset based, the cursor encodes the (slot, signature) of the last trade returned.
+func (tr *postgresTradeRepo) GetTrades(filter domain.TradeFilter) (domain.TradePage, error) {
+	conditions := []string{"wallet_address = $1"}
+	args := []any{filter.WalletAddress}
+	addCondition := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.Mint != "" {
+		addCondition("$%d IN (input_mint, output_mint)", filter.Mint)
+	}
+	if !filter.From.IsZero() {
+		addCondition("block_time >= $%d", filter.From)
+	}
+	if !filter.To.IsZero() {
+		addCondition("block_time <= $%d", filter.To)
+	}
+	if filter.Cursor != "" {
+		slot, signature, err := decodeTradeCursor(filter.Cursor)
+		if err != nil {
+			return domain.TradePage{}, err
+		}
+		args = append(args, slot, signature)
+		conditions = append(conditions, fmt.Sprintf("(slot, signature) < ($%d, $%d)", len(args)-1, len(args)))
+	}
+	// fetch one extra row to know if another page exists
+	args = append(args, filter.Limit+1)
+
+	query := fmt.Sprintf(`SELECT signature, wallet_address, slot, block_time, input_mint, input_amount,
+		output_mint, output_amount, COALESCE(venue, ''), COALESCE(usd_value, 0), usd_value IS NOT NULL
+		FROM trades WHERE %s
+		ORDER BY slot DESC, signature DESC
+		LIMIT $%d;`, strings.Join(conditions, " AND "), len(args))
+	rows, err := tr.db.Query(context.TODO(), query, args...)
+	if err != nil {
+		return domain.TradePage{}, fmt.Errorf("error querying trades: %w", err)
+	}
+	defer rows.Close()
+
+	trades, err := scanTrades(rows)
+	if err != nil {
+		return domain.TradePage{}, err
+	}
+	page := domain.TradePage{Trades: trades}
+	if len(page.Trades) > filter.Limit {
+		page.Trades = page.Trades[:filter.Limit]
+		last := page.Trades[len(page.Trades)-1]
+		page.NextCursor = encodeTradeCursor(last.Slot, last.Signature)
+	}
+	return page, nil
+}
+
+// `GetTradeHistory` fetches every trade for a wallet in execution order, oldest first
+func (tr *postgresTradeRepo) GetTradeHistory(walletAddress string) ([]domain.Trade, error) {
+	query := `SELECT signature, wallet_address, slot, block_time, input_mint, input_amount,
+		output_mint, output_amount, COALESCE(venue, ''), COALESCE(usd_value, 0), usd_value IS NOT NULL
+		FROM trades WHERE wallet_address = $1
+		ORDER BY slot ASC, signature ASC;`
+	rows, err := tr.db.Query(context.TODO(), query, walletAddress)
+	if err != nil {
+		return nil, fmt.Errorf("error querying trades: %w", err)
+	}
+	defer rows.Close()
+	return scanTrades(rows)
+}
+
+// `scanTrades` reads every row of a trades query into domain.Trade
+func scanTrades(rows pgx.Rows) ([]domain.Trade, error) {
+	trades := []domain.Trade{}
+	for rows.Next() {
+		var trade domain.Trade
+		err := rows.Scan(
+			&trade.Signature,
+			&trade.WalletAddress,
+			&trade.Slot,
+			&trade.BlockTime,
+			&trade.InputMint,
+			&trade.InputAmount,
+			&trade.OutputMint,
+			&trade.OutputAmount,
+			&trade.Venue,
+			&trade.USDValue,
+			&trade.Priced,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning trades: %w", err)
+		}
+		trades = append(trades, trade)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading trades: %w", err)
+	}
+	return trades, nil
+}
+
+// `encodeTradeCursor` encodes a trade's (slot, signature) into an opaque cursor
+func encodeTradeCursor(slot uint64, signature string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%s", slot, signature)))
+}
+
+// `decodeTradeCursor` decodes a cursor produced by encodeTradeCursor
+func decodeTradeCursor(cursor string) (uint64, string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, "", fmt.Errorf("%w: %v", domain.ErrInvalidCursor, err)
+	}
+	slotPart, signature, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return 0, "", domain.ErrInvalidCursor
+	}
+	slot, err := strconv.ParseUint(slotPart, 10, 64)
+	if err != nil {
+		return 0, "", fmt.Errorf("%w: %v", domain.ErrInvalidCursor, err)
+	}
+	return slot, signature, nil
+}
@@ -0,0 +1,47 @@
+// Package `solana` provides implementations of repository interfaces using Solana RPC methods,
+// and external API calls
+package solana
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/jakobsym/aura/internal/domain"
+	"github.com/jakobsym/aura/internal/repository"
+	"github.com/tidwall/gjson"
+)
+
+// default FX source, quoting every currency per 1 USD
+const defaultFXRatesURL = "https://api.frankfurter.app/latest?from=USD"
+
+// `fxRatesProvider` implements repository.FXProvider using a Frankfurter compatible rates API
+// i.e: {"base": "USD", "rates": {"EUR": 0.92, ...}}
+type fxRatesProvider struct {
+	client *http.Client
+	url    string
+}
+
+// `NewFXProvider` creates and returns an FXProvider reading USD based rates from url
+// falling back to the Frankfurter API when url is empty
+func NewFXProvider(url string) repository.FXProvider {
+	if url == "" {
+		url = defaultFXRatesURL
+	}
+	return &fxRatesProvider{client: &http.Client{}, url: url}
+}
+
+// `GetRate` retrieves the USD value of one unit of currency
+func (fp *fxRatesProvider) GetRate(ctx context.Context, currency string) (float64, error) {
+	body, err := getJSON(ctx, fp.client, fp.url)
+	if err != nil {
+		return 0, err
+	}
+	// rates: {<currency>: units per 1 USD}
+	perUSD := gjson.GetBytes(body, "rates."+strings.ToUpper(currency)).Float()
+	if perUSD <= 0 {
+		return 0, fmt.Errorf("%w: %s", domain.ErrPriceNotFound, currency)
+	}
+	return 1 / perUSD, nil
+}
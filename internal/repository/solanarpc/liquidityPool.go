@@ -0,0 +1,235 @@
+// Package `solana` provides implementations of repository interfaces using Solana RPC methods,
+// and external API calls
+package solana
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+
+	solanago "github.com/gagliardetto/solana-go"
+	solanarpc "github.com/gagliardetto/solana-go/rpc"
+	"github.com/jakobsym/aura/internal/domain"
+	"golang.org/x/sync/errgroup"
+)
+
+// Raydium AMM v4 liquidity state layout
+// https://github.com/raydium-io/raydium-amm/blob/master/program/src/state.rs
+const (
+	raydiumAMMv4Program    = "675kPX9MHTjS2zt1qfr1NYHuzeLXfQM9H24wFSUt1Mp8"
+	raydiumPoolSize        = 752
+	raydiumBaseVault       = 336
+	raydiumQuoteVault      = 368
+	raydiumBaseMint        = 400
+	raydiumQuoteMint       = 432
+	raydiumLPMint          = 464
+	raydiumLPReserveOffset = 720
+)
+
+// Raydium CPMM pool state layout
+// https://github.com/raydium-io/raydium-cp-swap/blob/master/programs/cp-swap/src/states/pool.rs
+const (
+	raydiumCPMMProgram  = "CPMMoo8L3F4NbTegBCKVNunggL7H1ZpdTHKxQB5qKP1C"
+	cpmmPoolSize        = 637
+	cpmmVault0          = 72
+	cpmmVault1          = 104
+	cpmmLPMint          = 136
+	cpmmMint0           = 168
+	cpmmMint1           = 200
+	cpmmLPReserveOffset = 333
+)
+
+// PumpSwap pool layout, accounts have grown fields over time so are matched by minimum size
+// https://github.com/pump-fun/pump-public-docs/blob/main/docs/PUMP_SWAP_README.md
+const (
+	pumpSwapProgram         = "pAMMBay6oceH9fJKBRHGP5D4bD4sWpmSwMn52FMfXEA"
+	pumpSwapMinPoolSize     = 211
+	pumpSwapBaseMint        = 43
+	pumpSwapQuoteMint       = 75
+	pumpSwapLPMint          = 107
+	pumpSwapBaseVault       = 139
+	pumpSwapQuoteVault      = 171
+	pumpSwapLPReserveOffset = 203
+)
+
+// `poolLayout` locates the mints, vaults and LP accounting of a venue's pool accounts
+type poolLayout struct {
+	venue      string
+	program    string
+	size       uint64 // exact account size, 0 to match any size of at least minSize
+	minSize    int
+	baseMint   int
+	quoteMint  int
+	baseVault  int
+	quoteVault int
+	lpMint     int // -1 for venues without an LP token (i.e: concentrated liquidity positions)
+	lpReserve  int // u64 LP minted and not withdrawn, -1 without an LP token
+}
+
+// `poolLayouts` are the venues GetLiquidityPool searches
+var poolLayouts = []poolLayout{
+	{
+		venue: domain.VenueRaydiumAMM, program: raydiumAMMv4Program, size: raydiumPoolSize, minSize: raydiumPoolSize,
+		baseMint: raydiumBaseMint, quoteMint: raydiumQuoteMint, baseVault: raydiumBaseVault, quoteVault: raydiumQuoteVault,
+		lpMint: raydiumLPMint, lpReserve: raydiumLPReserveOffset,
+	},
+	{
+		venue: domain.VenueRaydiumCPMM, program: raydiumCPMMProgram, size: cpmmPoolSize, minSize: cpmmPoolSize,
+		baseMint: cpmmMint0, quoteMint: cpmmMint1, baseVault: cpmmVault0, quoteVault: cpmmVault1,
+		lpMint: cpmmLPMint, lpReserve: cpmmLPReserveOffset,
+	},
+	{
+		venue: domain.VenuePumpFunAMM, program: pumpSwapProgram, minSize: pumpSwapMinPoolSize,
+		baseMint: pumpSwapBaseMint, quoteMint: pumpSwapQuoteMint, baseVault: pumpSwapBaseVault, quoteVault: pumpSwapQuoteVault,
+		lpMint: pumpSwapLPMint, lpReserve: pumpSwapLPReserveOffset,
+	},
+	{
+		venue: domain.VenueOrcaWhirlpool, program: orcaWhirlpoolProgram, size: whirlpoolSize, minSize: whirlpoolSize,
+		baseMint: whirlpoolMintA, quoteMint: whirlpoolMintB, baseVault: whirlpoolVaultA, quoteVault: whirlpoolVaultB,
+		lpMint: -1, lpReserve: -1,
+	},
+}
+
+// `GetLiquidityPool` finds the pool holding the most USD liquidity for a tokenAddress
+// across Raydium AMM v4, Raydium CPMM, PumpSwap and Orca Whirlpool pools pairing it against SOL, USDC or USDT.
+// Liquidity is valued from the pool's quote side, doubled as both sides hold equal value.
+// Returns domain.ErrPoolNotFound when the token has no such pool.
+func (sr *solanaTokenRepo) GetLiquidityPool(ctx context.Context, tokenAddress string) (domain.LiquidityPool, error) {
+	mint, err := solanago.PublicKeyFromBase58(tokenAddress)
+	if err != nil {
+		return domain.LiquidityPool{}, fmt.Errorf("%w: %v", domain.ErrInvalidAddress, err)
+	}
+
+	candidates, err := sr.findPools(ctx, mint)
+	if err != nil {
+		return domain.LiquidityPool{}, err
+	}
+	if len(candidates) == 0 {
+		return domain.LiquidityPool{}, domain.ErrPoolNotFound
+	}
+
+	vaults := make([]solanago.PublicKey, 0, 2*len(candidates))
+	for _, c := range candidates {
+		vaults = append(vaults, solanago.MustPublicKeyFromBase58(c.pool.BaseVault), solanago.MustPublicKeyFromBase58(c.pool.QuoteVault))
+	}
+	reserves, err := vaultReserves(ctx, sr.rpcClient, vaults)
+	if err != nil {
+		return domain.LiquidityPool{}, err
+	}
+
+	var (
+		best     *poolCandidate
+		solPrice float64
+	)
+	for i := range candidates {
+		c := &candidates[i]
+		tokenVault, quoteMint, quoteVault := c.pool.BaseVault, c.pool.QuoteMint, c.pool.QuoteVault
+		if c.pool.QuoteMint == tokenAddress {
+			tokenVault, quoteMint, quoteVault = c.pool.QuoteVault, c.pool.BaseMint, c.pool.BaseVault
+		}
+		tokenReserve := reserves[solanago.MustPublicKeyFromBase58(tokenVault)]
+		quoteReserve := reserves[solanago.MustPublicKeyFromBase58(quoteVault)]
+		if tokenReserve.amount <= 0 || quoteReserve.amount <= 0 {
+			continue
+		}
+		quotePrice := 1.0
+		if !domain.USDStablecoins[quoteMint] {
+			if solPrice == 0 {
+				price, err := sr.prices.GetPrice(ctx, domain.WrappedSOLMint)
+				if err != nil {
+					return domain.LiquidityPool{}, fmt.Errorf("error pricing sol: %w", err)
+				}
+				solPrice = price.Price
+			}
+			quotePrice = solPrice
+		}
+		c.pool.LiquidityUSD = 2 * quoteReserve.amount * quotePrice
+		if best == nil || c.pool.LiquidityUSD > best.pool.LiquidityUSD {
+			best = c
+		}
+	}
+	if best == nil {
+		return domain.LiquidityPool{}, domain.ErrPoolNotFound
+	}
+
+	pool := best.pool
+	if pool.LPMint == "" {
+		return pool, nil
+	}
+	supply, err := sr.rpcClient.GetTokenSupply(ctx, solanago.MustPublicKeyFromBase58(pool.LPMint), solanarpc.CommitmentConfirmed)
+	if err != nil {
+		return domain.LiquidityPool{}, fmt.Errorf("error fetching lp supply: %w", err)
+	}
+	if pool.LPSupply, err = strconv.ParseFloat(supply.Value.UiAmountString, 64); err != nil {
+		return domain.LiquidityPool{}, fmt.Errorf("error converting lp supply: %w", err)
+	}
+	pool.LPReserve = float64(best.lpReserve) / math.Pow10(int(supply.Value.Decimals))
+	return pool, nil
+}
+
+// `poolCandidate` is a pool found for a token, before its liquidity is measured
+// lpReserve holds the raw LP reserve, scaled once the LP mint's decimals are known
+type poolCandidate struct {
+	pool      domain.LiquidityPool
+	lpReserve uint64
+}
+
+// `findPools` returns the pools of every poolLayouts venue pairing mint against SOL, USDC or USDT
+// each side of each venue is scanned concurrently
+func (sr *solanaTokenRepo) findPools(ctx context.Context, mint solanago.PublicKey) ([]poolCandidate, error) {
+	type scan struct {
+		layout poolLayout
+		offset int
+	}
+	var scans []scan
+	for _, layout := range poolLayouts {
+		scans = append(scans, scan{layout, layout.baseMint}, scan{layout, layout.quoteMint})
+	}
+	results := make([]solanarpc.GetProgramAccountsResult, len(scans))
+	g, gctx := errgroup.WithContext(ctx)
+	for i, s := range scans {
+		g.Go(func() error {
+			accounts, err := programAccountsByMint(gctx, sr.rpcClient, s.layout.program, s.layout.size, uint64(s.offset), mint)
+			results[i] = accounts
+			return err
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	var candidates []poolCandidate
+	for i, accounts := range results {
+		layout := scans[i].layout
+		for _, account := range accounts {
+			data := account.Account.Data.GetBinary()
+			if len(data) < layout.minSize {
+				continue
+			}
+			pool := domain.LiquidityPool{
+				Address:    account.Pubkey.String(),
+				Venue:      layout.venue,
+				BaseMint:   pubkeyAt(data, layout.baseMint).String(),
+				QuoteMint:  pubkeyAt(data, layout.quoteMint).String(),
+				BaseVault:  pubkeyAt(data, layout.baseVault).String(),
+				QuoteVault: pubkeyAt(data, layout.quoteVault).String(),
+			}
+			other := pool.QuoteMint
+			if other == mint.String() {
+				other = pool.BaseMint
+			}
+			if _, ok := poolQuoteMints[other]; !ok {
+				continue
+			}
+			candidate := poolCandidate{pool: pool}
+			if layout.lpMint >= 0 {
+				candidate.pool.LPMint = pubkeyAt(data, layout.lpMint).String()
+				candidate.lpReserve = binary.LittleEndian.Uint64(data[layout.lpReserve:])
+			}
+			candidates = append(candidates, candidate)
+		}
+	}
+	return candidates, nil
+}
@@ -0,0 +1,198 @@
+// Package `solana` provides implementations of repository interfaces using Solana RPC methods,
+// and external API calls
+package solana
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"os"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/jakobsym/aura/internal/domain"
+)
+
+// off-chain metadata fetch limits
+const (
+	offChainTimeout      = 5 * time.Second
+	offChainMaxBytes     = 256 << 10
+	offChainMaxRedirects = 3
+)
+
+// `offChainClient` fetches token chosen URIs, refusing to connect to
+// loopback, private, link-local or unspecified addresses, on every redirect hop
+var offChainClient = &http.Client{
+	Timeout: offChainTimeout,
+	Transport: &http.Transport{
+		// no proxy, the dialer must see the address actually connected to
+		Proxy: nil,
+		DialContext: (&net.Dialer{
+			Timeout: offChainTimeout,
+			// runs after DNS resolution, against the resolved address
+			Control: func(network, address string, _ syscall.RawConn) error {
+				host, _, err := net.SplitHostPort(address)
+				if err != nil {
+					return err
+				}
+				ip, err := netip.ParseAddr(host)
+				if err != nil {
+					return fmt.Errorf("unexpected dial address: %q", address)
+				}
+				return checkPublicIP(ip)
+			},
+		}).DialContext,
+		TLSHandshakeTimeout:   offChainTimeout,
+		ResponseHeaderTimeout: offChainTimeout,
+	},
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		if len(via) > offChainMaxRedirects {
+			return fmt.Errorf("stopped after %d redirects", offChainMaxRedirects)
+		}
+		if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+			return fmt.Errorf("unsupported redirect scheme: %q", req.URL.Scheme)
+		}
+		// hostnames are checked by the dialer once resolved
+		if ip, err := netip.ParseAddr(req.URL.Hostname()); err == nil {
+			return checkPublicIP(ip)
+		}
+		return nil
+	},
+}
+
+// `checkPublicIP` rejects addresses that are not publicly routable
+func checkPublicIP(ip netip.Addr) error {
+	ip = ip.Unmap()
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return fmt.Errorf("refusing to connect to non-public address %s", ip)
+	}
+	return nil
+}
+
+const (
+	defaultIPFSGateway    = "https://ipfs.io/ipfs/"
+	defaultArweaveGateway = "https://arweave.net/"
+)
+
+// `offChainJSON` is the Metaplex token standard JSON a metadata URI points to
+// launchpads (i.e: pump.fun) put socials at the top level rather than under extensions
+type offChainJSON struct {
+	Image       string `json:"image"`
+	Description string `json:"description"`
+	ExternalURL string `json:"external_url"`
+	Website     string `json:"website"`
+	Twitter     string `json:"twitter"`
+	Telegram    string `json:"telegram"`
+	Extensions  struct {
+		Website  string `json:"website"`
+		Twitter  string `json:"twitter"`
+		Telegram string `json:"telegram"`
+	} `json:"extensions"`
+}
+
+// `GetOffChainMetadata` fetches and validates the off-chain JSON metadata a token's URI points to
+// ipfs:// and ar:// URIs, as well as public IPFS gateway links, are served through configured gateways.
+// Responses are bounded by offChainTimeout and offChainMaxBytes, and links that are not http(s) dropped.
+// URIs are token controlled, so requests go through offChainClient which only reaches public addresses.
+func (sr *solanaTokenRepo) GetOffChainMetadata(ctx context.Context, uri string) (domain.OffChainMetadata, error) {
+	target, err := gatewayURL(uri)
+	if err != nil {
+		return domain.OffChainMetadata{}, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, offChainTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", target, nil)
+	if err != nil {
+		return domain.OffChainMetadata{}, fmt.Errorf("error building req: %w", err)
+	}
+	res, err := offChainClient.Do(req)
+	if err != nil {
+		return domain.OffChainMetadata{}, fmt.Errorf("error receiving response: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return domain.OffChainMetadata{}, fmt.Errorf("unexpected status code: %d", res.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, offChainMaxBytes+1))
+	if err != nil {
+		return domain.OffChainMetadata{}, fmt.Errorf("error reading res body: %w", err)
+	}
+	if len(body) > offChainMaxBytes {
+		return domain.OffChainMetadata{}, fmt.Errorf("metadata exceeds %d bytes", offChainMaxBytes)
+	}
+	var raw offChainJSON
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return domain.OffChainMetadata{}, fmt.Errorf("invalid metadata json: %w", err)
+	}
+
+	metadata := domain.OffChainMetadata{
+		Description: strings.TrimSpace(raw.Description),
+		Website:     validLink(firstNonEmpty(raw.Extensions.Website, raw.Website, raw.ExternalURL)),
+		Twitter:     validLink(firstNonEmpty(raw.Extensions.Twitter, raw.Twitter)),
+		Telegram:    validLink(firstNonEmpty(raw.Extensions.Telegram, raw.Telegram)),
+	}
+	if image, err := gatewayURL(raw.Image); err == nil {
+		metadata.Image = image
+	}
+	return metadata, nil
+}
+
+// `gatewayURL` rewrites a metadata URI into an http(s) URL
+// i.e: ipfs://<cid>/<path> -> <IPFS_GATEWAY><cid>/<path>, ar://<txid> -> <ARWEAVE_GATEWAY><txid>
+func gatewayURL(uri string) (string, error) {
+	uri = strings.TrimSpace(uri)
+	ipfsGateway := envOr("IPFS_GATEWAY", defaultIPFSGateway)
+	switch {
+	case strings.HasPrefix(uri, "ipfs://"):
+		return ipfsGateway + strings.TrimPrefix(strings.TrimPrefix(uri, "ipfs://"), "ipfs/"), nil
+	case strings.HasPrefix(uri, "ar://"):
+		return envOr("ARWEAVE_GATEWAY", defaultArweaveGateway) + strings.TrimPrefix(uri, "ar://"), nil
+	}
+
+	u, err := url.Parse(uri)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("unsupported metadata uri: %q", uri)
+	}
+	// public gateway links, some of which are no longer served, go through the configured gateway
+	if _, path, ok := strings.Cut(u.Path, "/ipfs/"); ok {
+		return ipfsGateway + path, nil
+	}
+	return u.String(), nil
+}
+
+// `validLink` returns link if it is an absolute http(s) URL, otherwise an empty string
+func validLink(link string) string {
+	link = strings.TrimSpace(link)
+	u, err := url.Parse(link)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ""
+	}
+	return u.String()
+}
+
+// `firstNonEmpty` returns the first non-empty value
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// `envOr` reads key from the environment, falling back to def
+func envOr(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
@@ -0,0 +1,346 @@
+// Package `solana` provides implementations of repository interfaces using Solana RPC methods,
+// and external API calls
+package solana
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"slices"
+	"sync"
+	"time"
+
+	solanago "github.com/gagliardetto/solana-go"
+	solanarpc "github.com/gagliardetto/solana-go/rpc"
+	"github.com/jakobsym/aura/internal/domain"
+	"github.com/jakobsym/aura/internal/repository"
+	"github.com/tidwall/gjson"
+	"golang.org/x/sync/errgroup"
+)
+
+// Orca Whirlpool account layout
+// https://github.com/orca-so/whirlpools/blob/main/programs/whirlpool/src/state/whirlpool.rs
+const (
+	orcaWhirlpoolProgram = "whirLbMiicVdio4qvUfM5KAg6Ct8VwpYzGff3uctyCc"
+	whirlpoolSize        = 653
+	whirlpoolSqrtPrice   = 65 // u128 Q64.64
+	whirlpoolMintA       = 101
+	whirlpoolVaultA      = 133
+	whirlpoolMintB       = 181
+	whirlpoolVaultB      = 213
+)
+
+// on-chain pricing constants
+const (
+	solUsdcPool = "58oQChx4yWmvKdwLLZzBi4ChoCc2fqCUWBkwMihLYQo2" // Raydium AMM v4 SOL / USDC
+	solPriceTTL = 10 * time.Second                               // how long the SOL / USD reference price is reused
+)
+
+// `poolQuoteMints` maps the mints a pool may price a token against to whether they are USD-pegged
+var poolQuoteMints = map[string]bool{
+	domain.WrappedSOLMint: false,
+	domain.USDCMint:       true,
+	domain.USDTMint:       true,
+}
+
+// `onChainPool` is a pool pairing a token against one of poolQuoteMints
+type onChainPool struct {
+	address    string
+	quoteMint  string
+	tokenVault solanago.PublicKey
+	quoteVault solanago.PublicKey
+	sqrtPrice  *big.Int // Whirlpools only, nil for constant product pools
+	tokenIsA   bool     // token is the Whirlpool's mint A
+}
+
+// `vaultReserve` is the balance held by a pool vault
+type vaultReserve struct {
+	amount   float64
+	decimals int
+}
+
+// `onChainPriceProvider` implements repository.PriceProvider by reading Raydium and Orca pool state
+// straight from the chain, pricing tokens before any HTTP price API has indexed them
+type onChainPriceProvider struct {
+	rpcClient *solanarpc.Client
+
+	mu          sync.Mutex
+	solPrice    float64
+	solQuotedAt time.Time
+}
+
+// `NewOnChainPriceProvider` creates and returns a Raydium / Orca pool implementation
+// of the PriceProvider interface.
+func NewOnChainPriceProvider(c *solanarpc.Client) repository.PriceProvider {
+	return &onChainPriceProvider{rpcClient: c}
+}
+
+// `Name` identifies the on-chain price provider
+func (op *onChainPriceProvider) Name() string {
+	return "onchain"
+}
+
+// `GetPrice` prices a token from its most liquid Raydium AMM v4 or Orca Whirlpool pool against SOL, USDC or USDT
+// SOL quoted prices are converted to USD through the solUsdcPool reference pool.
+// Liquidity reports the USD value held by both sides of the selected pool.
+func (op *onChainPriceProvider) GetPrice(ctx context.Context, tokenAddress string) (domain.TokenPrice, error) {
+	mint, err := solanago.PublicKeyFromBase58(tokenAddress)
+	if err != nil {
+		return domain.TokenPrice{}, fmt.Errorf("%w: %v", domain.ErrInvalidAddress, err)
+	}
+	// quote mints pair with too many pools to scan, so are priced directly
+	if pegged, ok := poolQuoteMints[tokenAddress]; ok {
+		price := 1.0
+		if !pegged {
+			if price, err = op.getSolPrice(ctx); err != nil {
+				return domain.TokenPrice{}, err
+			}
+		}
+		return domain.TokenPrice{Mint: tokenAddress, Price: price, Provider: op.Name(), QuotedAt: time.Now().UTC()}, nil
+	}
+
+	pools, err := op.findPools(ctx, mint)
+	if err != nil {
+		return domain.TokenPrice{}, err
+	}
+	if len(pools) == 0 {
+		return domain.TokenPrice{}, fmt.Errorf("%w: %s", domain.ErrPriceNotFound, tokenAddress)
+	}
+
+	vaults := make([]solanago.PublicKey, 0, 2*len(pools))
+	for _, pool := range pools {
+		vaults = append(vaults, pool.tokenVault, pool.quoteVault)
+	}
+	reserves, err := op.getReserves(ctx, vaults)
+	if err != nil {
+		return domain.TokenPrice{}, err
+	}
+
+	var (
+		best      domain.TokenPrice
+		solPrice  float64
+		quotedSol bool
+	)
+	for _, pool := range pools {
+		tokenReserve, quoteReserve := reserves[pool.tokenVault], reserves[pool.quoteVault]
+		if tokenReserve.amount <= 0 || quoteReserve.amount <= 0 {
+			continue
+		}
+
+		quotePrice := 1.0
+		if !poolQuoteMints[pool.quoteMint] {
+			if !quotedSol {
+				if solPrice, err = op.getSolPrice(ctx); err != nil {
+					return domain.TokenPrice{}, err
+				}
+				quotedSol = true
+			}
+			quotePrice = solPrice
+		}
+
+		price := quoteReserve.amount / tokenReserve.amount
+		if pool.sqrtPrice != nil {
+			price = whirlpoolPrice(pool, tokenReserve.decimals, quoteReserve.decimals)
+		}
+		liquidity := (tokenReserve.amount*price + quoteReserve.amount) * quotePrice
+		if liquidity > best.Liquidity {
+			best = domain.TokenPrice{Mint: tokenAddress, Price: price * quotePrice, Pool: pool.address, Liquidity: liquidity}
+		}
+	}
+	if best.Price <= 0 || math.IsInf(best.Price, 0) {
+		return domain.TokenPrice{}, fmt.Errorf("%w: %s", domain.ErrPriceNotFound, tokenAddress)
+	}
+	best.Provider = op.Name()
+	best.QuotedAt = time.Now().UTC()
+	return best, nil
+}
+
+// `findPools` returns the Raydium AMM v4 and Orca Whirlpool pools pairing mint against poolQuoteMints
+// each side of each program is scanned concurrently
+func (op *onChainPriceProvider) findPools(ctx context.Context, mint solanago.PublicKey) ([]onChainPool, error) {
+	scans := []struct {
+		program string
+		size    uint64
+		offset  uint64
+	}{
+		{raydiumAMMv4Program, raydiumPoolSize, raydiumBaseMint},
+		{raydiumAMMv4Program, raydiumPoolSize, raydiumQuoteMint},
+		{orcaWhirlpoolProgram, whirlpoolSize, whirlpoolMintA},
+		{orcaWhirlpoolProgram, whirlpoolSize, whirlpoolMintB},
+	}
+	results := make([]solanarpc.GetProgramAccountsResult, len(scans))
+	g, gctx := errgroup.WithContext(ctx)
+	for i, scan := range scans {
+		g.Go(func() error {
+			accounts, err := programAccountsByMint(gctx, op.rpcClient, scan.program, scan.size, scan.offset, mint)
+			results[i] = accounts
+			return err
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	var pools []onChainPool
+	for i, accounts := range results {
+		for _, account := range accounts {
+			data := account.Account.Data.GetBinary()
+			if uint64(len(data)) < scans[i].size {
+				continue
+			}
+			var pool onChainPool
+			if scans[i].program == raydiumAMMv4Program {
+				pool = raydiumQuotePool(data, mint)
+			} else {
+				pool = whirlpoolQuotePool(data, mint)
+			}
+			if _, ok := poolQuoteMints[pool.quoteMint]; !ok {
+				continue
+			}
+			pool.address = account.Pubkey.String()
+			pools = append(pools, pool)
+		}
+	}
+	return pools, nil
+}
+
+// `raydiumQuotePool` reads the vaults of a Raydium AMM v4 pool from the token's side
+func raydiumQuotePool(data []byte, mint solanago.PublicKey) onChainPool {
+	pool := onChainPool{
+		quoteMint:  pubkeyAt(data, raydiumQuoteMint).String(),
+		tokenVault: pubkeyAt(data, raydiumBaseVault),
+		quoteVault: pubkeyAt(data, raydiumQuoteVault),
+	}
+	if !pubkeyAt(data, raydiumBaseMint).Equals(mint) {
+		pool.quoteMint = pubkeyAt(data, raydiumBaseMint).String()
+		pool.tokenVault, pool.quoteVault = pool.quoteVault, pool.tokenVault
+	}
+	return pool
+}
+
+// `whirlpoolQuotePool` reads the vaults and sqrt price of an Orca Whirlpool from the token's side
+func whirlpoolQuotePool(data []byte, mint solanago.PublicKey) onChainPool {
+	// u128 little endian -> big endian for big.Int
+	sqrtPrice := slices.Clone(data[whirlpoolSqrtPrice : whirlpoolSqrtPrice+16])
+	slices.Reverse(sqrtPrice)
+
+	pool := onChainPool{
+		quoteMint:  pubkeyAt(data, whirlpoolMintB).String(),
+		tokenVault: pubkeyAt(data, whirlpoolVaultA),
+		quoteVault: pubkeyAt(data, whirlpoolVaultB),
+		sqrtPrice:  new(big.Int).SetBytes(sqrtPrice),
+		tokenIsA:   true,
+	}
+	if !pubkeyAt(data, whirlpoolMintA).Equals(mint) {
+		pool.quoteMint = pubkeyAt(data, whirlpoolMintA).String()
+		pool.tokenVault, pool.quoteVault = pool.quoteVault, pool.tokenVault
+		pool.tokenIsA = false
+	}
+	return pool
+}
+
+// `whirlpoolPrice` converts a Whirlpool's Q64.64 sqrt price into the token's price in its quote mint
+// concentrated liquidity pools are priced from their current tick rather than the vault ratio
+func whirlpoolPrice(pool onChainPool, tokenDecimals, quoteDecimals int) float64 {
+	sqrt, _ := new(big.Float).Quo(new(big.Float).SetInt(pool.sqrtPrice), new(big.Float).SetMantExp(big.NewFloat(1), 64)).Float64()
+	// price of A in B, raw units
+	price := sqrt * sqrt
+	if !pool.tokenIsA {
+		if price == 0 {
+			return 0
+		}
+		price = 1 / price
+	}
+	return price * math.Pow10(tokenDecimals-quoteDecimals)
+}
+
+// `getReserves` reads the balances of token vaults
+func (op *onChainPriceProvider) getReserves(ctx context.Context, vaults []solanago.PublicKey) (map[solanago.PublicKey]vaultReserve, error) {
+	return vaultReserves(ctx, op.rpcClient, vaults)
+}
+
+// getMultipleAccounts accepts at most this many accounts per call
+const maxAccountsPerCall = 100
+
+// `vaultReserves` reads the balances of token vaults, batching getMultipleAccounts calls
+func vaultReserves(ctx context.Context, c *solanarpc.Client, vaults []solanago.PublicKey) (map[solanago.PublicKey]vaultReserve, error) {
+	reserves := make(map[solanago.PublicKey]vaultReserve, len(vaults))
+	for batch := range slices.Chunk(vaults, maxAccountsPerCall) {
+		out, err := c.GetMultipleAccountsWithOpts(ctx, batch,
+			&solanarpc.GetMultipleAccountsOpts{Commitment: solanarpc.CommitmentConfirmed, Encoding: solanago.EncodingJSONParsed},
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching pool vaults: %w", err)
+		}
+		for i, account := range out.Value {
+			if i >= len(batch) || account == nil {
+				continue
+			}
+			// parsed.info.tokenAmount: {amount, decimals, uiAmountString}
+			tokenAmount := gjson.GetBytes(account.Data.GetRawJSON(), "parsed.info.tokenAmount")
+			reserves[batch[i]] = vaultReserve{
+				amount:   tokenAmount.Get("uiAmountString").Float(),
+				decimals: int(tokenAmount.Get("decimals").Int()),
+			}
+		}
+	}
+	return reserves, nil
+}
+
+// `getSolPrice` returns the USD price of SOL from the solUsdcPool vaults
+// reused for solPriceTTL
+func (op *onChainPriceProvider) getSolPrice(ctx context.Context) (float64, error) {
+	op.mu.Lock()
+	defer op.mu.Unlock()
+	if time.Since(op.solQuotedAt) < solPriceTTL {
+		return op.solPrice, nil
+	}
+
+	info, err := op.rpcClient.GetAccountInfoWithOpts(ctx, solanago.MustPublicKeyFromBase58(solUsdcPool),
+		&solanarpc.GetAccountInfoOpts{Commitment: solanarpc.CommitmentConfirmed, Encoding: solanago.EncodingBase64},
+	)
+	if err != nil {
+		return 0, fmt.Errorf("error fetching sol reference pool: %w", err)
+	}
+	data := info.GetBinary()
+	if len(data) < raydiumPoolSize {
+		return 0, fmt.Errorf("unexpected sol reference pool size: %d", len(data))
+	}
+	baseVault, quoteVault := pubkeyAt(data, raydiumBaseVault), pubkeyAt(data, raydiumQuoteVault)
+	reserves, err := op.getReserves(ctx, []solanago.PublicKey{baseVault, quoteVault})
+	if err != nil {
+		return 0, err
+	}
+	if reserves[baseVault].amount <= 0 {
+		return 0, fmt.Errorf("%w: sol reference pool is empty", domain.ErrPriceNotFound)
+	}
+
+	op.solPrice = reserves[quoteVault].amount / reserves[baseVault].amount
+	op.solQuotedAt = time.Now()
+	return op.solPrice, nil
+}
+
+// `programAccountsByMint` returns the accounts of program sized size holding mint at offset
+// a zero size matches accounts of any size, for layouts that have grown over time
+func programAccountsByMint(ctx context.Context, c *solanarpc.Client, program string, size, offset uint64, mint solanago.PublicKey) (solanarpc.GetProgramAccountsResult, error) {
+	filters := []solanarpc.RPCFilter{{Memcmp: &solanarpc.RPCFilterMemcmp{Offset: offset, Bytes: mint.Bytes()}}}
+	if size > 0 {
+		filters = append(filters, solanarpc.RPCFilter{DataSize: size})
+	}
+	accounts, err := c.GetProgramAccountsWithOpts(ctx, solanago.MustPublicKeyFromBase58(program),
+		&solanarpc.GetProgramAccountsOpts{
+			Encoding: solanago.EncodingBase64,
+			Filters:  filters,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching pools: %w", err)
+	}
+	return accounts, nil
+}
+
+// `pubkeyAt` reads the public key stored at offset of an account's data
+func pubkeyAt(data []byte, offset int) solanago.PublicKey {
+	return solanago.PublicKeyFromBytes(data[offset : offset+32])
+}
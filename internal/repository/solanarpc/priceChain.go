@@ -0,0 +1,116 @@
+// Package `solana` provides implementations of repository interfaces using Solana RPC methods,
+// and external API calls
+package solana
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/jakobsym/aura/internal/domain"
+	"github.com/jakobsym/aura/internal/repository"
+)
+
+// provider health constants
+const (
+	providerMaxFailures = 3                // consecutive failures before a provider is benched
+	providerCooldown    = 30 * time.Second // how long a benched provider is skipped
+)
+
+// `ChainedProvider` is a price provider in a chain, bounded by its own timeout
+// i.e: HTTP APIs answer quickly, on-chain pool scans need longer
+type ChainedProvider struct {
+	Provider repository.PriceProvider
+	Timeout  time.Duration
+}
+
+// `priceChain` implements repository.PriceProvider by querying providers in order
+// until one returns a price. Providers failing repeatedly are skipped for providerCooldown.
+type priceChain struct {
+	providers []ChainedProvider
+
+	mu     sync.Mutex
+	health map[string]*providerHealth
+}
+
+// `providerHealth` tracks the recent failures of a price provider
+type providerHealth struct {
+	failures     int
+	benchedUntil time.Time
+}
+
+// `NewPriceChain` creates a PriceProvider falling back through providers in the given order
+// each provider call is bounded by the provider's Timeout
+func NewPriceChain(providers ...ChainedProvider) repository.PriceProvider {
+	health := make(map[string]*providerHealth, len(providers))
+	for _, chained := range providers {
+		health[chained.Provider.Name()] = &providerHealth{}
+	}
+	return &priceChain{providers: providers, health: health}
+}
+
+// `Name` identifies the fallback chain
+func (pc *priceChain) Name() string {
+	return "chain"
+}
+
+// `GetPrice` retrieves the price of a token from the first healthy provider able to quote it
+// benched providers are still tried when no healthy provider is left.
+// Returns domain.ErrPriceNotFound when every provider answered without a price.
+func (pc *priceChain) GetPrice(ctx context.Context, tokenAddress string) (domain.TokenPrice, error) {
+	healthy, benched := pc.partition()
+	var errs []error
+	for _, chained := range append(healthy, benched...) {
+		price, err := pc.query(ctx, chained, tokenAddress)
+		if err == nil {
+			return price, nil
+		}
+		if ctx.Err() != nil {
+			return domain.TokenPrice{}, ctx.Err()
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", chained.Provider.Name(), err))
+	}
+	return domain.TokenPrice{}, errors.Join(errs...)
+}
+
+// `query` calls a single provider under its timeout, recording the outcome
+// a token the provider has no price for is not a provider failure
+func (pc *priceChain) query(ctx context.Context, chained ChainedProvider, tokenAddress string) (domain.TokenPrice, error) {
+	provider := chained.Provider
+	ctx, cancel := context.WithTimeout(ctx, chained.Timeout)
+	defer cancel()
+	price, err := provider.GetPrice(ctx, tokenAddress)
+
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	health := pc.health[provider.Name()]
+	switch {
+	case err == nil || errors.Is(err, domain.ErrPriceNotFound):
+		health.failures = 0
+	default:
+		health.failures++
+		if health.failures >= providerMaxFailures {
+			health.benchedUntil = time.Now().Add(providerCooldown)
+			log.Printf("price provider %s benched after %d failures: %v", provider.Name(), health.failures, err)
+		}
+	}
+	return price, err
+}
+
+// `partition` splits providers into healthy and benched, preserving their order
+func (pc *priceChain) partition() (healthy, benched []ChainedProvider) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	now := time.Now()
+	for _, chained := range pc.providers {
+		if now.Before(pc.health[chained.Provider.Name()].benchedUntil) {
+			benched = append(benched, chained)
+		} else {
+			healthy = append(healthy, chained)
+		}
+	}
+	return healthy, benched
+}
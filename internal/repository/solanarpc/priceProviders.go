@@ -0,0 +1,114 @@
+// Package `solana` provides implementations of repository interfaces using Solana RPC methods,
+// and external API calls
+package solana
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/jakobsym/aura/internal/domain"
+	"github.com/jakobsym/aura/internal/repository"
+	"github.com/tidwall/gjson"
+)
+
+// `jupiterPriceProvider` implements repository.PriceProvider using the Jupiter price API
+type jupiterPriceProvider struct {
+	client *http.Client
+}
+
+// `NewJupiterPriceProvider` creates and returns a Jupiter price API implementation
+// of the PriceProvider interface.
+func NewJupiterPriceProvider() repository.PriceProvider {
+	return &jupiterPriceProvider{client: &http.Client{}}
+}
+
+// `Name` identifies the Jupiter price provider
+func (jp *jupiterPriceProvider) Name() string {
+	return "jupiter"
+}
+
+// `GetPrice` retrieves the USD price of a token from the Jupiter price API
+func (jp *jupiterPriceProvider) GetPrice(ctx context.Context, tokenAddress string) (domain.TokenPrice, error) {
+	url := fmt.Sprintf("https://api.jup.ag/price/v2?ids=%s", tokenAddress)
+	body, err := getJSON(ctx, jp.client, url)
+	if err != nil {
+		return domain.TokenPrice{}, err
+	}
+	// data: {<mint>: {id, type, price}}, <mint> is null when unpriced
+	price := gjson.GetBytes(body, "data."+tokenAddress+".price")
+	if !price.Exists() {
+		return domain.TokenPrice{}, fmt.Errorf("%w: %s", domain.ErrPriceNotFound, tokenAddress)
+	}
+	return domain.TokenPrice{Mint: tokenAddress, Price: price.Float(), Provider: jp.Name(), QuotedAt: time.Now().UTC()}, nil
+}
+
+// `dexScreenerPriceProvider` implements repository.PriceProvider using the DexScreener API
+type dexScreenerPriceProvider struct {
+	client *http.Client
+}
+
+// `NewDexScreenerPriceProvider` creates and returns a DexScreener API implementation
+// of the PriceProvider interface.
+func NewDexScreenerPriceProvider() repository.PriceProvider {
+	return &dexScreenerPriceProvider{client: &http.Client{}}
+}
+
+// `Name` identifies the DexScreener price provider
+func (dp *dexScreenerPriceProvider) Name() string {
+	return "dexscreener"
+}
+
+// `GetPrice` retrieves the USD price of a token from its most liquid Solana pair on DexScreener
+// only pairs quoting the token as their base are considered, priceUsd being the base token's price
+func (dp *dexScreenerPriceProvider) GetPrice(ctx context.Context, tokenAddress string) (domain.TokenPrice, error) {
+	url := fmt.Sprintf("https://api.dexscreener.com/latest/dex/tokens/%s", tokenAddress)
+	body, err := getJSON(ctx, dp.client, url)
+	if err != nil {
+		return domain.TokenPrice{}, err
+	}
+
+	var (
+		price     float64
+		liquidity float64
+		pool      string
+		found     bool
+	)
+	// pairs: [{chainId, pairAddress, baseToken: {address}, priceUsd, liquidity: {usd}}]
+	gjson.GetBytes(body, "pairs").ForEach(func(_, pair gjson.Result) bool {
+		if pair.Get("chainId").String() != "solana" || pair.Get("baseToken.address").String() != tokenAddress {
+			return true
+		}
+		if pairLiquidity := pair.Get("liquidity.usd").Float(); !found || pairLiquidity > liquidity {
+			price, liquidity, pool, found = pair.Get("priceUsd").Float(), pairLiquidity, pair.Get("pairAddress").String(), true
+		}
+		return true
+	})
+	if !found || price <= 0 {
+		return domain.TokenPrice{}, fmt.Errorf("%w: %s", domain.ErrPriceNotFound, tokenAddress)
+	}
+	return domain.TokenPrice{Mint: tokenAddress, Price: price, Provider: dp.Name(), QuotedAt: time.Now().UTC(), Pool: pool, Liquidity: liquidity}, nil
+}
+
+// `getJSON` performs a GET request bound to ctx, returning the body of a 200 response
+func getJSON(ctx context.Context, client *http.Client, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error building req: %w", err)
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error receiving response: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", res.StatusCode)
+	}
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading res body: %w", err)
+	}
+	return body, nil
+}
@@ -0,0 +1,159 @@
+// Package `solana` provides implementations of repository interfaces using Solana RPC methods,
+// and external API calls
+package solana
+
+import (
+	"strings"
+	"sync"
+
+	solanago "github.com/gagliardetto/solana-go"
+	"github.com/jakobsym/aura/internal/domain"
+)
+
+// `programInfo` describes a known on-chain program that executes or routes swaps
+type programInfo struct {
+	venue      string
+	aggregator bool // routes trades through other venues rather than holding liquidity
+}
+
+var (
+	programsMu sync.RWMutex
+	// `knownPrograms` maps program IDs to the venue they belong to
+	// extended at runtime through RegisterProgram
+	knownPrograms = map[string]programInfo{
+		"JUP6LkbZbjS1jKKwapdHNy74zcZ3tLUZoi5QNyVTaV4":  {venue: domain.VenueJupiter, aggregator: true},
+		"JUP4Fb2cqiRUcaTHdrPC8h2gNsA8fvKXYbXUJJqRUX2":  {venue: domain.VenueJupiter, aggregator: true},
+		"675kPX9MHTjS2zt1qfr1NYHuzeLXfQM9H24wFSUt1Mp8": {venue: domain.VenueRaydiumAMM},
+		"CAMMCzo5YL8w4VFF8KVHrK22GGUsp5VTaW7grrKgrWqK": {venue: domain.VenueRaydiumCLMM},
+		"CPMMoo8L3F4NbTegBCKVNunggL7H1ZpdTHKxQB5qKP1C": {venue: domain.VenueRaydiumCPMM},
+		"whirLbMiicVdio4qvUfM5KAg6Ct8VwpYzGff3uctyCc":  {venue: domain.VenueOrcaWhirlpool},
+		"Eo7WjKq67rjJQSZxS6z3YkapzY3eMj6Xy8X5EQVn5UaB": {venue: domain.VenueMeteora},
+		"LBUZKhRxPF3XUpBCjp4YzTKgLccjZhTSDM9YuVaPwxo":  {venue: domain.VenueMeteoraDLMM},
+		"6EF8rrecthR5Dkzon8Nwu78hRvfCKubJ14M5uBEwF6P":  {venue: domain.VenuePumpFun},
+		"pAMMBay6oceH9fJKBRHGP5D4bD4sWpmSwMn52FMfXEA":  {venue: domain.VenuePumpFunAMM},
+	}
+
+	// `stakePrograms` maps native and liquid staking program IDs to their name
+	stakePrograms = map[string]string{
+		"Stake11111111111111111111111111111111111111": "Native Stake",
+		"MarBmsSgKXdrN1egZf5sqe1TMai9K1rChYNDJgjq7aD": "Marinade",
+		"SPoo1Ku8WFXoNDMHPsrGSTSG1Y47rzgn41SLUNakuHy": "SPL Stake Pool",
+		"SP12tWFxD9oJsVWNavTTBZvMbA6gkAmxtVgxdqvyvhY": "Sanctum",
+	}
+
+	// `nftMarketplaces` maps NFT marketplace program IDs to their name
+	nftMarketplaces = map[string]string{
+		"M2mx93ekt1fmXSVkTrUL9xVFHkmME8HTUi5Cyc5aF7K": "Magic Eden",
+		"mmm3XBJg5gk8XJxEKBvdgptZz6SgK4tXvn36sodowMc": "Magic Eden",
+		"TSWAPaqyCSx2KABk68Shruf4rp7CxcNi8hAsbdwmHbN": "Tensor",
+		"TCMPhJdwDryooaGtiocG1u3xcYbRpiJzb283XfCZsDp": "Tensor",
+	}
+)
+
+// `RegisterProgram` adds or overrides the venue a programId is attributed to
+// aggregator marks programs that route trades through other venues
+func RegisterProgram(programId, venue string, aggregator bool) {
+	programsMu.Lock()
+	defer programsMu.Unlock()
+	knownPrograms[programId] = programInfo{venue: venue, aggregator: aggregator}
+}
+
+// `lookupProgram` returns the programInfo registered for a programId
+func lookupProgram(programId string) (programInfo, bool) {
+	programsMu.RLock()
+	defer programsMu.RUnlock()
+	info, ok := knownPrograms[programId]
+	return info, ok
+}
+
+// `invokedPrograms` returns the program IDs invoked by a txn in execution order
+// resolved from top-level and inner instructions, as well as `Program <id> invoke` log lines
+func invokedPrograms(payload domain.TransactionResult) []string {
+	keys := resolveAccountKeys(payload)
+	seen := make(map[string]bool)
+	var programs []string
+	add := func(programId string) {
+		if programId == "" || seen[programId] {
+			return
+		}
+		seen[programId] = true
+		programs = append(programs, programId)
+	}
+
+	inner := make(map[int][]domain.CompiledInstruction)
+	for _, ix := range payload.Result.Meta.InnerInstructions {
+		inner[ix.Index] = ix.Instructions
+	}
+	for i, ix := range payload.Result.Transaction.Message.Instructions {
+		if ix.ProgramIDIndex < len(keys) {
+			add(keys[ix.ProgramIDIndex])
+		}
+		for _, innerIx := range inner[i] {
+			if innerIx.ProgramIDIndex < len(keys) {
+				add(keys[innerIx.ProgramIDIndex])
+			}
+		}
+	}
+
+	// logs: "Program <id> invoke [depth]"
+	for _, line := range payload.Result.Meta.LogMessages {
+		fields := strings.Fields(line)
+		if len(fields) >= 3 && fields[0] == "Program" && fields[2] == "invoke" {
+			add(fields[1])
+		}
+	}
+	return programs
+}
+
+// `identifyVenue` attributes a txn to the venue that executed it
+// returning the first known liquidity venue and the first aggregator that routed it.
+// When only an aggregator is recognised it is reported as the venue.
+func identifyVenue(payload domain.TransactionResult) (venue, router string) {
+	for _, programId := range invokedPrograms(payload) {
+		info, ok := lookupProgram(programId)
+		if !ok {
+			continue
+		}
+		if info.aggregator {
+			if router == "" {
+				router = info.venue
+			}
+			continue
+		}
+		if venue == "" {
+			venue = info.venue
+		}
+	}
+	if venue == "" {
+		return router, ""
+	}
+	return venue, router
+}
+
+// `tokenInstructions` returns the SPL Token / Token-2022 instructions executed by a txn
+// i.e: MintTo, Burn, parsed from "Program log: Instruction: <name>" lines logged
+// while a token program is at the top of the invoke stack
+func tokenInstructions(payload domain.TransactionResult) map[string]bool {
+	tokenPrograms := map[string]bool{
+		solanago.TokenProgramID.String():     true,
+		solanago.Token2022ProgramID.String(): true,
+	}
+	instructions := make(map[string]bool)
+	var stack []string
+	for _, line := range payload.Result.Meta.LogMessages {
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) >= 3 && fields[0] == "Program" && fields[2] == "invoke":
+			stack = append(stack, fields[1])
+		case len(fields) >= 3 && fields[0] == "Program" && (fields[2] == "success" || fields[2] == "failed:"):
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		case strings.HasPrefix(line, "Program log: Instruction: "):
+			if len(stack) > 0 && tokenPrograms[stack[len(stack)-1]] {
+				instructions[strings.TrimPrefix(line, "Program log: Instruction: ")] = true
+			}
+		}
+	}
+	return instructions
+}
@@ -0,0 +1,50 @@
+// Package `solana` provides implementations of repository interfaces using Solana RPC methods,
+// and external API calls
+package solana
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/jakobsym/aura/internal/domain"
+	"github.com/jakobsym/aura/internal/repository"
+	"github.com/tidwall/gjson"
+)
+
+// Pyth benchmarks candles for SOL/USD, by minute between two unix timestamps
+const pythSOLHistoryURL = "https://benchmarks.pyth.network/v1/shims/tradingview/history?symbol=Crypto.SOL%%2FUSD&resolution=1&from=%d&to=%d"
+
+// how far before a requested time a candle is still accepted as its price
+const solHistoryWindow = 5 * time.Minute
+
+// `pythSOLPriceHistory` implements repository.SOLPriceHistory using the Pyth benchmarks API
+type pythSOLPriceHistory struct {
+	client *http.Client
+}
+
+// `NewSOLPriceHistory` creates and returns a Pyth benchmarks implementation
+// of the SOLPriceHistory interface.
+func NewSOLPriceHistory() repository.SOLPriceHistory {
+	return &pythSOLPriceHistory{client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// `GetSOLPriceAt` retrieves the close of the last SOL/USD minute candle at or before at
+// returns domain.ErrPriceNotFound when no candle exists within solHistoryWindow
+func (ph *pythSOLPriceHistory) GetSOLPriceAt(ctx context.Context, at time.Time) (float64, error) {
+	url := fmt.Sprintf(pythSOLHistoryURL, at.Add(-solHistoryWindow).Unix(), at.Unix())
+	body, err := getJSON(ctx, ph.client, url)
+	if err != nil {
+		return 0, err
+	}
+	// {s: "ok" | "no_data", t: [unix], c: [close]}, candles oldest first
+	if s := gjson.GetBytes(body, "s").String(); s != "ok" {
+		return 0, fmt.Errorf("%w: SOL at %s", domain.ErrPriceNotFound, at.Format(time.RFC3339))
+	}
+	closes := gjson.GetBytes(body, "c").Array()
+	if len(closes) == 0 || closes[len(closes)-1].Float() <= 0 {
+		return 0, fmt.Errorf("%w: SOL at %s", domain.ErrPriceNotFound, at.Format(time.RFC3339))
+	}
+	return closes[len(closes)-1].Float(), nil
+}
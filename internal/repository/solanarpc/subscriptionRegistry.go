@@ -0,0 +1,208 @@
+// Package `solana` provides implementations of repository interfaces using Solana RPC methods,
+// and external API calls
+package solana
+
+import "sync"
+
+// `subscriptionRegistry` records the lifecycle of Helius log subscriptions
+// request ID -> subscription ID -> wallet address -> set of userIds
+// and hands out unique, monotonically increasing JSON-RPC request IDs.
+type subscriptionRegistry struct {
+	mu            sync.RWMutex
+	lastRequestId int
+	requests      map[int]string                 // JSON-RPC request ID -> wallet address
+	subscriptions map[int]string                 // Helius subscription ID -> wallet address
+	wallets       map[string]*walletSubscription // wallet address -> subscription state
+}
+
+// `walletSubscription` holds the Helius subscription backing a wallet
+// and the users tracking it
+type walletSubscription struct {
+	subscriptionId int // zero until Helius confirms the subscription
+	subscribing    bool
+	users          map[int]struct{}
+}
+
+// `newSubscriptionRegistry` creates an empty subscriptionRegistry
+func newSubscriptionRegistry() *subscriptionRegistry {
+	return &subscriptionRegistry{
+		requests:      make(map[int]string),
+		subscriptions: make(map[int]string),
+		wallets:       make(map[string]*walletSubscription),
+	}
+}
+
+// `nextRequestID` returns a unique JSON-RPC request ID
+func (r *subscriptionRegistry) nextRequestID() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lastRequestId++
+	return r.lastRequestId
+}
+
+// `addUser` registers userId as tracking walletAddress
+// Returns true if the caller must issue a logsSubscribe for the wallet,
+// false if a subscription is already live or in flight.
+func (r *subscriptionRegistry) addUser(walletAddress string, userId int) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ws, ok := r.wallets[walletAddress]
+	if !ok {
+		ws = &walletSubscription{users: make(map[int]struct{})}
+		r.wallets[walletAddress] = ws
+	}
+	ws.users[userId] = struct{}{}
+	if ws.subscriptionId != 0 || ws.subscribing {
+		return false
+	}
+	ws.subscribing = true
+	return true
+}
+
+// `beginSubscribe` marks a tracked wallet as subscribing
+// Returns false if the wallet is untracked, or its subscription is already live or in flight.
+func (r *subscriptionRegistry) beginSubscribe(walletAddress string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ws, ok := r.wallets[walletAddress]
+	if !ok || ws.subscriptionId != 0 || ws.subscribing {
+		return false
+	}
+	ws.subscribing = true
+	return true
+}
+
+// `addRequest` allocates a request ID for a subscription request on walletAddress
+func (r *subscriptionRegistry) addRequest(walletAddress string) int {
+	requestId := r.nextRequestID()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests[requestId] = walletAddress
+	return requestId
+}
+
+// `resolveRequest` binds the subscription ID Helius returned for requestId to its wallet
+// replacing any previous subscription ID held by that wallet.
+// Returns the replaced subscription ID, or zero if there was none.
+func (r *subscriptionRegistry) resolveRequest(requestId, subscriptionId int) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	walletAddress, ok := r.requests[requestId]
+	if !ok {
+		return 0
+	}
+	delete(r.requests, requestId)
+
+	ws, ok := r.wallets[walletAddress]
+	if !ok {
+		// wallet was untracked while the request was in flight
+		return 0
+	}
+	previous := ws.subscriptionId
+	if previous != 0 {
+		delete(r.subscriptions, previous)
+	}
+	ws.subscriptionId = subscriptionId
+	ws.subscribing = false
+	r.subscriptions[subscriptionId] = walletAddress
+	return previous
+}
+
+// `failRequest` discards a request that never received a subscription ID
+// dropping any subscription ID the wallet held so a later subscribe for it is retried.
+func (r *subscriptionRegistry) failRequest(requestId int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	walletAddress, ok := r.requests[requestId]
+	if !ok {
+		return
+	}
+	delete(r.requests, requestId)
+	if ws, ok := r.wallets[walletAddress]; ok {
+		delete(r.subscriptions, ws.subscriptionId)
+		ws.subscriptionId = 0
+		ws.subscribing = false
+	}
+}
+
+// `resetSubscriptions` drops every Helius subscription ID and in flight request
+// as they do not survive a reconnect. Tracked wallets and their users are kept.
+func (r *subscriptionRegistry) resetSubscriptions() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	clear(r.requests)
+	clear(r.subscriptions)
+	for _, ws := range r.wallets {
+		ws.subscriptionId = 0
+		ws.subscribing = false
+	}
+}
+
+// `walletFor` returns the wallet address a Helius subscription ID belongs to
+func (r *subscriptionRegistry) walletFor(subscriptionId int) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	walletAddress, ok := r.subscriptions[subscriptionId]
+	return walletAddress, ok
+}
+
+// `subscriptionFor` returns the Helius subscription ID held by walletAddress
+func (r *subscriptionRegistry) subscriptionFor(walletAddress string) (int, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	ws, ok := r.wallets[walletAddress]
+	if !ok || ws.subscriptionId == 0 {
+		return 0, false
+	}
+	return ws.subscriptionId, true
+}
+
+// `removeUser` drops userId from walletAddress
+func (r *subscriptionRegistry) removeUser(walletAddress string, userId int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if ws, ok := r.wallets[walletAddress]; ok {
+		delete(ws.users, userId)
+	}
+}
+
+// `removeUsers` drops every user from walletAddress, once no user tracks it
+func (r *subscriptionRegistry) removeUsers(walletAddress string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if ws, ok := r.wallets[walletAddress]; ok {
+		clear(ws.users)
+	}
+}
+
+// `removeWallet` drops an untracked walletAddress once its subscriptionId is cancelled
+// Returns false if a user started tracking the wallet meanwhile,
+// its subscription is then cleared so the wallet can be resubscribed.
+func (r *subscriptionRegistry) removeWallet(walletAddress string, subscriptionId int) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ws, ok := r.wallets[walletAddress]
+	if !ok {
+		return true
+	}
+	delete(r.subscriptions, subscriptionId)
+	if ws.subscriptionId == subscriptionId {
+		ws.subscriptionId = 0
+	}
+	if len(ws.users) > 0 {
+		return false
+	}
+	delete(r.wallets, walletAddress)
+	return true
+}
+
+// `trackedWallets` returns every wallet address currently registered
+func (r *subscriptionRegistry) trackedWallets() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	wallets := make([]string, 0, len(r.wallets))
+	for walletAddress := range r.wallets {
+		wallets = append(wallets, walletAddress)
+	}
+	return wallets
+}
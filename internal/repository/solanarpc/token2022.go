@@ -0,0 +1,201 @@
+// Package `solana` provides implementations of repository interfaces using Solana RPC methods,
+// and external API calls
+package solana
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	solanago "github.com/gagliardetto/solana-go"
+	"github.com/jakobsym/aura/internal/domain"
+)
+
+// SPL Token mint layout
+// https://github.com/solana-labs/solana-program-library/blob/master/token/program/src/state.rs
+const (
+	mintSize            = 82
+	mintDecimalsOffset  = 44
+	mintFreezeAuthority = 46
+	// Token-2022 pads mints to the size of a token account before the account type and TLV entries
+	extensionAccountTypeOffset = 165
+	extensionMintAccountType   = 1
+)
+
+// Token-2022 extension types
+// https://github.com/solana-labs/solana-program-library/blob/master/token/program-2022/src/extension/mod.rs
+var extensionNames = map[uint16]string{
+	1:  "transfer_fee_config",
+	3:  "mint_close_authority",
+	4:  "confidential_transfer_mint",
+	6:  "default_account_state",
+	9:  "non_transferable",
+	10: "interest_bearing_config",
+	12: "permanent_delegate",
+	14: "transfer_hook",
+	16: "confidential_transfer_fee_config",
+	18: "metadata_pointer",
+	19: "token_metadata",
+	20: "group_pointer",
+	21: "token_group",
+	22: "group_member_pointer",
+	23: "token_group_member",
+	24: "confidential_mint_burn",
+	25: "scaled_ui_amount",
+	26: "pausable",
+}
+
+const (
+	extTransferFeeConfig   = 1
+	extMintCloseAuthority  = 3
+	extDefaultAccountState = 6
+	extNonTransferable     = 9
+	extPermanentDelegate   = 12
+	extTransferHook        = 14
+	extMetadataPointer     = 18
+	extTokenMetadata       = 19
+	extPausable            = 26
+)
+
+// `parseMint` decodes a mint account owned by the SPL Token or Token-2022 program
+// returning the Token-2022 token_metadata extension, when the mint embeds its own metadata
+func parseMint(mint string, owner solanago.PublicKey, data []byte) (domain.MintInfo, *domain.TokenMetadata, error) {
+	if len(data) < mintSize {
+		return domain.MintInfo{}, nil, fmt.Errorf("mint account too short: %d bytes", len(data))
+	}
+	info := domain.MintInfo{
+		Decimals:        int(data[mintDecimalsOffset]),
+		MintAuthority:   optionalPubkey(data[0:36]),
+		FreezeAuthority: optionalPubkey(data[mintFreezeAuthority:mintSize]),
+	}
+	switch {
+	case owner.Equals(solanago.TokenProgramID):
+		info.Program = "spl-token"
+		return info, nil, nil
+	case owner.Equals(solanago.Token2022ProgramID):
+		info.Program = "spl-token-2022"
+	default:
+		return domain.MintInfo{}, nil, fmt.Errorf("%s is not a token mint, owned by %s", mint, owner)
+	}
+
+	info.Extensions = &domain.TokenExtensions{Enabled: []string{}}
+	if len(data) <= extensionAccountTypeOffset || data[extensionAccountTypeOffset] != extensionMintAccountType {
+		return info, nil, nil
+	}
+
+	var metadata *domain.TokenMetadata
+	tlv := data[extensionAccountTypeOffset+1:]
+	for len(tlv) >= 4 {
+		extType := binary.LittleEndian.Uint16(tlv[0:2])
+		length := int(binary.LittleEndian.Uint16(tlv[2:4]))
+		if extType == 0 || len(tlv) < 4+length {
+			break
+		}
+		value := tlv[4 : 4+length]
+		tlv = tlv[4+length:]
+
+		if name, ok := extensionNames[extType]; ok {
+			info.Extensions.Enabled = append(info.Extensions.Enabled, name)
+		}
+		switch extType {
+		case extTransferFeeConfig:
+			info.Extensions.TransferFee = parseTransferFee(value, info.Decimals)
+		case extMintCloseAuthority:
+			info.Extensions.MintCloseAuthority = nonZeroPubkey(value)
+		case extDefaultAccountState:
+			// AccountState: 0 uninitialized, 1 initialized, 2 frozen
+			info.Extensions.DefaultAccountFrozen = len(value) > 0 && value[0] == 2
+		case extNonTransferable:
+			info.Extensions.NonTransferable = true
+		case extPermanentDelegate:
+			info.Extensions.PermanentDelegate = nonZeroPubkey(value)
+		case extTransferHook:
+			// authority (32) | program_id (32)
+			if len(value) >= 64 {
+				info.Extensions.TransferHookProgram = nonZeroPubkey(value[32:64])
+			}
+		case extMetadataPointer:
+			// authority (32) | metadata_address (32)
+			if len(value) >= 64 {
+				info.Extensions.MetadataAddress = nonZeroPubkey(value[32:64])
+			}
+		case extTokenMetadata:
+			if md, err := parseTokenMetadataExtension(mint, value); err == nil {
+				metadata = &md
+			}
+		case extPausable:
+			// authority (32) | paused (1)
+			info.Extensions.Paused = len(value) >= 33 && value[32] == 1
+		}
+	}
+	return info, metadata, nil
+}
+
+// `parseTransferFee` decodes the TransferFeeConfig extension
+// authorities (64) | withheld_amount (8) | older_transfer_fee (18) | newer_transfer_fee (18)
+// each transfer fee being epoch (8) | maximum_fee (8) | transfer_fee_basis_points (2).
+// The newer fee is reported, as it applies from its epoch onwards.
+func parseTransferFee(value []byte, decimals int) *domain.TransferFee {
+	const newerFeeOffset = 64 + 8 + 18
+	if len(value) < newerFeeOffset+18 {
+		return nil
+	}
+	fee := value[newerFeeOffset:]
+	return &domain.TransferFee{
+		Epoch:       binary.LittleEndian.Uint64(fee[0:8]),
+		MaximumFee:  float64(binary.LittleEndian.Uint64(fee[8:16])) / math.Pow10(decimals),
+		BasisPoints: binary.LittleEndian.Uint16(fee[16:18]),
+	}
+}
+
+// `parseTokenMetadataExtension` decodes the borsh encoded TokenMetadata extension
+// update_authority (32) | mint (32) | name | symbol | uri | additional_metadata
+func parseTokenMetadataExtension(mint string, value []byte) (domain.TokenMetadata, error) {
+	if len(value) < 64 {
+		return domain.TokenMetadata{}, errors.New("token metadata extension too short")
+	}
+	rest := value[64:]
+	var fields [3]string
+	for i := range fields {
+		if len(rest) < 4 {
+			return domain.TokenMetadata{}, errors.New("token metadata extension truncated")
+		}
+		n := int(binary.LittleEndian.Uint32(rest[0:4]))
+		if len(rest) < 4+n {
+			return domain.TokenMetadata{}, errors.New("token metadata extension truncated")
+		}
+		fields[i] = string(rest[4 : 4+n])
+		rest = rest[4+n:]
+	}
+	// metadata stays mutable until its update authority is cleared
+	updateAuthority := nonZeroPubkey(value[0:32])
+	return domain.TokenMetadata{
+		Mint:            mint,
+		Name:            fields[0],
+		Symbol:          fields[1],
+		URI:             fields[2],
+		UpdateAuthority: updateAuthority,
+		Mutable:         updateAuthority != "",
+	}, nil
+}
+
+// `optionalPubkey` decodes a COption<Pubkey>: a u32 tag followed by 32 bytes
+func optionalPubkey(data []byte) string {
+	if len(data) < 36 || binary.LittleEndian.Uint32(data[0:4]) == 0 {
+		return ""
+	}
+	return solanago.PublicKeyFromBytes(data[4:36]).String()
+}
+
+// `nonZeroPubkey` decodes an OptionalNonZeroPubkey, where all zero bytes mean none
+func nonZeroPubkey(data []byte) string {
+	if len(data) < 32 {
+		return ""
+	}
+	key := solanago.PublicKeyFromBytes(data[:32])
+	if key.IsZero() {
+		return ""
+	}
+	return key.String()
+}
@@ -0,0 +1,193 @@
+// Package `solana` provides implementations of repository interfaces using Solana RPC methods,
+// and external API calls
+package solana
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	bin "github.com/gagliardetto/binary"
+	token_metadata "github.com/gagliardetto/metaplex-go/clients/token-metadata"
+	solanago "github.com/gagliardetto/solana-go"
+	solanarpc "github.com/gagliardetto/solana-go/rpc"
+	"github.com/jakobsym/aura/internal/domain"
+	"golang.org/x/sync/singleflight"
+)
+
+// upper bound on a single metadata lookup shared by coalesced callers
+const metadataLookupTimeout = 10 * time.Second
+
+// how long a mint without metadata is remembered before being looked up again
+const metadataNotFoundTTL = time.Minute
+
+// `metadataEntry` is a cached lookup result, either metadata or a not found error
+// not found results expire at notFoundUntil, as metadata may still be created
+type metadataEntry struct {
+	mint          string
+	metadata      domain.TokenMetadata
+	err           error
+	notFoundUntil time.Time
+}
+
+// `TokenMetadataResolver` resolves token metadata by decoding Metaplex metadata accounts
+// Resolved metadata is kept in an in-memory LRU cache, alongside mints found to have none
+// for metadataNotFoundTTL. Concurrent lookups of the same mint are coalesced into a single RPC call.
+type TokenMetadataResolver struct {
+	rpcClient *solanarpc.Client
+	group     singleflight.Group
+
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*list.Element // mint -> element holding a metadataEntry
+	order    *list.List               // most recently used at the front
+}
+
+// `NewTokenMetadataResolver` creates a TokenMetadataResolver caching up to capacity mints
+func NewTokenMetadataResolver(c *solanarpc.Client, capacity int) *TokenMetadataResolver {
+	return &TokenMetadataResolver{
+		rpcClient: c,
+		capacity:  max(capacity, 1),
+		entries:   make(map[string]*list.Element),
+		order:     list.New(),
+	}
+}
+
+// `Resolve` retrieves the metadata of a token mint, from cache when possible
+// callers waiting on a coalesced lookup return early if their ctx is cancelled
+func (r *TokenMetadataResolver) Resolve(ctx context.Context, mint string) (domain.TokenMetadata, error) {
+	if entry, ok := r.get(mint); ok {
+		return entry.metadata, entry.err
+	}
+
+	ch := r.group.DoChan(mint, func() (any, error) {
+		// detached from the first caller so its cancellation doesn't fail the others
+		lookupCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), metadataLookupTimeout)
+		defer cancel()
+		metadata, err := r.fetch(lookupCtx, mint)
+		if errors.Is(err, domain.ErrTokenNotFound) {
+			r.add(metadataEntry{mint: mint, err: err, notFoundUntil: time.Now().Add(metadataNotFoundTTL)})
+		}
+		if err != nil {
+			return nil, err
+		}
+		r.add(metadataEntry{mint: mint, metadata: metadata})
+		return metadata, nil
+	})
+	select {
+	case res := <-ch:
+		if res.Err != nil {
+			return domain.TokenMetadata{}, res.Err
+		}
+		return res.Val.(domain.TokenMetadata), nil
+	case <-ctx.Done():
+		return domain.TokenMetadata{}, ctx.Err()
+	}
+}
+
+// `get` returns the cached lookup of a mint, marking it as recently used
+// expired not found results are dropped and reported as a miss
+func (r *TokenMetadataResolver) get(mint string) (metadataEntry, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	elem, ok := r.entries[mint]
+	if !ok {
+		return metadataEntry{}, false
+	}
+	entry := elem.Value.(metadataEntry)
+	if entry.err != nil && time.Now().After(entry.notFoundUntil) {
+		r.order.Remove(elem)
+		delete(r.entries, mint)
+		return metadataEntry{}, false
+	}
+	r.order.MoveToFront(elem)
+	return entry, true
+}
+
+// `add` caches a lookup, evicting the least recently used mint once over capacity
+func (r *TokenMetadataResolver) add(entry metadataEntry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if elem, ok := r.entries[entry.mint]; ok {
+		elem.Value = entry
+		r.order.MoveToFront(elem)
+		return
+	}
+	r.entries[entry.mint] = r.order.PushFront(entry)
+	if r.order.Len() > r.capacity {
+		oldest := r.order.Back()
+		r.order.Remove(oldest)
+		delete(r.entries, oldest.Value.(metadataEntry).mint)
+	}
+}
+
+// `fetch` retrieves and decodes the Metaplex metadata account of a mint
+// falling back to the Token-2022 token_metadata extension for mints without one.
+// Both accounts are loaded in a single call.
+func (r *TokenMetadataResolver) fetch(ctx context.Context, tokenAddress string) (domain.TokenMetadata, error) {
+	mint, err := solanago.PublicKeyFromBase58(tokenAddress)
+	if err != nil {
+		return domain.TokenMetadata{}, fmt.Errorf("%w: %v", domain.ErrInvalidAddress, err)
+	}
+
+	// Find where metadata is stored
+	// using token mint, and token programID
+	seeds := [][]byte{
+		[]byte("metadata"),
+		token_metadata.ProgramID.Bytes(),
+		mint.Bytes(),
+	}
+
+	/* Extraction */
+	// Find program derived address based on seeds, and ProgramID
+	mdAddr, _, err := solanago.FindProgramAddress(seeds, token_metadata.ProgramID)
+	if err != nil {
+		return domain.TokenMetadata{}, fmt.Errorf("unable to find metadata address: %w", err)
+	}
+	// Get account info using derived mdAddr, alongside the mint
+	accounts, err := r.rpcClient.GetMultipleAccountsWithOpts(ctx, []solanago.PublicKey{mdAddr, mint},
+		&solanarpc.GetMultipleAccountsOpts{Encoding: solanago.EncodingBase64},
+	)
+	if err != nil {
+		return domain.TokenMetadata{}, fmt.Errorf("unable to find account info: %w", err)
+	}
+	if len(accounts.Value) != 2 {
+		return domain.TokenMetadata{}, fmt.Errorf("unexpected account count: %d", len(accounts.Value))
+	}
+	mdAcc, mintAcc := accounts.Value[0], accounts.Value[1]
+	if mdAcc == nil {
+		if mintAcc == nil {
+			return domain.TokenMetadata{}, fmt.Errorf("%w: mint %s", domain.ErrTokenNotFound, tokenAddress)
+		}
+		_, metadata, err := parseMint(tokenAddress, mintAcc.Owner, mintAcc.Data.GetBinary())
+		if err != nil {
+			return domain.TokenMetadata{}, err
+		}
+		if metadata == nil {
+			return domain.TokenMetadata{}, fmt.Errorf("%w: no metadata for %s", domain.ErrTokenNotFound, tokenAddress)
+		}
+		return *metadata, nil
+	}
+
+	/* Transformation */
+	var metadata token_metadata.Metadata
+	// Deserialize binary data, loading into metadata variable
+	decoder := bin.NewBorshDecoder(mdAcc.Data.GetBinary())
+	if err := metadata.UnmarshalWithDecoder(decoder); err != nil {
+		return domain.TokenMetadata{}, fmt.Errorf("unable to deserialize data: %w", err)
+	}
+
+	// fields are null padded to a fixed length
+	return domain.TokenMetadata{
+		Mint:            tokenAddress,
+		Name:            strings.TrimRight(metadata.Data.Name, "\x00"),
+		Symbol:          strings.TrimRight(metadata.Data.Symbol, "\x00"),
+		URI:             strings.TrimRight(metadata.Data.Uri, "\x00"),
+		UpdateAuthority: metadata.UpdateAuthority.String(),
+		Mutable:         metadata.IsMutable,
+	}, nil
+}
@@ -5,16 +5,13 @@ package solana
 import (
 	"context"
 	"fmt"
-	"io"
-	"net/http"
-	"regexp"
 	"strconv"
 	"time"
 
-	bin "github.com/gagliardetto/binary"
 	token_metadata "github.com/gagliardetto/metaplex-go/clients/token-metadata"
 	solanago "github.com/gagliardetto/solana-go"
 	solanarpc "github.com/gagliardetto/solana-go/rpc"
+	"github.com/jakobsym/aura/internal/domain"
 	"github.com/jakobsym/aura/internal/repository"
 	"github.com/tidwall/gjson"
 )
@@ -22,12 +19,14 @@ import (
 // `solanaTokenRepo` implements the solanarpc.SolanaTokenRepo interface using a solanarpc.Client
 type solanaTokenRepo struct {
 	rpcClient *solanarpc.Client
+	metadata  *TokenMetadataResolver
+	prices    repository.PriceProvider
 }
 
 // `NewSolanaTokenRepo` creates and returns a new solanarpc.Client implementation
 // of the SolanaTokenRepo interface.
-func NewSolanaTokenRepo(c *solanarpc.Client) repository.SolanaTokenRepo {
-	return &solanaTokenRepo{rpcClient: c}
+func NewSolanaTokenRepo(c *solanarpc.Client, mr *TokenMetadataResolver, pp repository.PriceProvider) repository.SolanaTokenRepo {
+	return &solanaTokenRepo{rpcClient: c, metadata: mr, prices: pp}
 }
 
 // `SolanaRpcConnection` creates a new connection to Solana mainnet
@@ -36,40 +35,13 @@ func SolanaRpcConnection() *solanarpc.Client {
 	return solanarpc.New("https://api.mainnet-beta.solana.com")
 }
 
-// `GetTokenPrice` retrieves current price of a token in USD from the jupiter API
-// TODO: Make such that denominating asset included in req body
-// Currently only USD
-func (sr *solanaTokenRepo) GetTokenPrice(ctx context.Context, tokenAddress string) (float64, error) {
-	client := &http.Client{}
-	url := fmt.Sprintf("https://api.jup.ag/price/v2?ids=%s", tokenAddress)
-	req, err := http.NewRequest("GET", url, nil)
-	//	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return 0, fmt.Errorf("error building req: %w", err)
-	}
-
-	res, err := client.Do(req)
-	if err != nil {
-		return 0, fmt.Errorf("error receiving response: %w", err)
-	}
-	defer res.Body.Close()
-	/*
-		if res.StatusCode != http.StatusOK {
-			return 0, fmt.Errorf("unexpected status code: %w", err)
-		}
-	*/
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return 0, fmt.Errorf("error reading res body: %w", err)
-	}
-	price := gjson.Get(string(body), "data."+tokenAddress+".price")
-	if !price.Exists() {
-		return 0, fmt.Errorf("price not found: %s", tokenAddress)
-	}
-	return price.Float(), nil
+// `GetTokenPrice` retrieves current price of a token in USD from the configured PriceProvider
+// prices are converted to other quote currencies by the service layer
+func (sr *solanaTokenRepo) GetTokenPrice(ctx context.Context, tokenAddress string) (domain.TokenPrice, error) {
+	return sr.prices.GetPrice(ctx, tokenAddress)
 }
 
-// `GetTokenSupply` retrieves current ciculating supply for a given Solana tokenAddress
+// `GetTokenSupply` retrieves current total supply for a given Solana tokenAddress
 // returns supply as float64 for easier calculations
 func (sr *solanaTokenRepo) GetTokenSupply(ctx context.Context, tokenAddress string) (float64, error) {
 	mint := solanago.MustPublicKeyFromBase58(tokenAddress)
@@ -85,68 +57,142 @@ func (sr *solanaTokenRepo) GetTokenSupply(ctx context.Context, tokenAddress stri
 	return supplyInt, nil
 }
 
+// `GetHeldBalance` sums the tokenAddress balance held by addresses
+// addresses that are not token accounts of the mint are treated as owners,
+// summing every token account of the mint they own (i.e: wallets, vesting PDAs, the incinerator)
+func (sr *solanaTokenRepo) GetHeldBalance(ctx context.Context, tokenAddress string, addresses []string) (float64, error) {
+	mint, err := solanago.PublicKeyFromBase58(tokenAddress)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", domain.ErrInvalidAddress, err)
+	}
+	keys := make([]solanago.PublicKey, len(addresses))
+	for i, address := range addresses {
+		if keys[i], err = solanago.PublicKeyFromBase58(address); err != nil {
+			return 0, fmt.Errorf("%w: %v", domain.ErrInvalidAddress, err)
+		}
+	}
+	if len(keys) == 0 {
+		return 0, nil
+	}
+
+	out, err := sr.rpcClient.GetMultipleAccountsWithOpts(ctx, keys,
+		&solanarpc.GetMultipleAccountsOpts{Commitment: solanarpc.CommitmentConfirmed, Encoding: solanago.EncodingJSONParsed},
+	)
+	if err != nil {
+		return 0, fmt.Errorf("error fetching held accounts: %w", err)
+	}
+
+	var held float64
+	for i, key := range keys {
+		if i < len(out.Value) && out.Value[i] != nil {
+			// parsed: {type: "account", info: {mint, owner, tokenAmount}}
+			parsed := gjson.GetBytes(out.Value[i].Data.GetRawJSON(), "parsed")
+			if parsed.Get("type").String() == "account" {
+				if parsed.Get("info.mint").String() == tokenAddress {
+					held += parsed.Get("info.tokenAmount.uiAmount").Float()
+				}
+				continue
+			}
+		}
+		owned, err := sr.rpcClient.GetTokenAccountsByOwner(ctx, key,
+			&solanarpc.GetTokenAccountsConfig{Mint: &mint},
+			&solanarpc.GetTokenAccountsOpts{Commitment: solanarpc.CommitmentConfirmed, Encoding: solanago.EncodingJSONParsed},
+		)
+		if err != nil {
+			return 0, fmt.Errorf("error fetching accounts owned by %s: %w", key, err)
+		}
+		for _, account := range owned.Value {
+			held += gjson.GetBytes(account.Account.Data.GetRawJSON(), "parsed.info.tokenAmount.uiAmount").Float()
+		}
+	}
+	return held, nil
+}
+
 // `GetTokenFDV` calculates the fully dilued valuation of a given token
 func (sr *solanaTokenRepo) GetTokenFDV(ctx context.Context, price float64, supply float64) float64 {
 	return (price * supply)
 }
 
-// `GetTokenNameAndSymbol` retrieves the name and symbol for a Solana token
-// by fetching and decoding its metadata account
-// returns a string slice [name, symbol]
-func (sr *solanaTokenRepo) GetTokenNameAndSymbol(ctx context.Context, tokenAddress string) ([]string, error) {
-	// Convert tokenAddress to a Solana PublicKey
-	mint := solanago.MustPublicKeyFromBase58(tokenAddress)
+// `GetTokenMetadata` retrieves the name, symbol and metadata URI for a Solana token
+// through the shared TokenMetadataResolver
+func (sr *solanaTokenRepo) GetTokenMetadata(ctx context.Context, tokenAddress string) (domain.TokenMetadata, error) {
+	return sr.metadata.Resolve(ctx, tokenAddress)
+}
 
-	// Find where metadata is stored
-	// using token mint, and token programID
-	seeds := [][]byte{
-		[]byte("metadata"),
-		token_metadata.ProgramID.Bytes(),
-		mint.Bytes(),
+// `GetMintInfo` retrieves and decodes the mint account of a Solana token
+// detecting its owning program, and parsing Token-2022 extensions
+func (sr *solanaTokenRepo) GetMintInfo(ctx context.Context, tokenAddress string) (domain.MintInfo, error) {
+	mint, err := solanago.PublicKeyFromBase58(tokenAddress)
+	if err != nil {
+		return domain.MintInfo{}, fmt.Errorf("%w: %v", domain.ErrInvalidAddress, err)
+	}
+	acc, err := sr.rpcClient.GetAccountInfoWithOpts(ctx, mint, &solanarpc.GetAccountInfoOpts{Encoding: solanago.EncodingBase64})
+	if err != nil {
+		return domain.MintInfo{}, fmt.Errorf("unable to find account info: %w", err)
 	}
+	info, _, err := parseMint(tokenAddress, acc.Value.Owner, acc.Value.Data.GetBinary())
+	return info, err
+}
 
-	/* Extraction */
-	// Find program derived address based on seeds, and ProgramID
-	mdAddr, _, err := solanago.FindProgramAddress(seeds, token_metadata.ProgramID)
+// `GetLargestHolders` retrieves the largest token accounts of a tokenAddress (up to 20)
+// resolving the owner of each account
+func (sr *solanaTokenRepo) GetLargestHolders(ctx context.Context, tokenAddress string) ([]domain.TokenHolder, error) {
+	mint, err := solanago.PublicKeyFromBase58(tokenAddress)
 	if err != nil {
-		return []string{}, fmt.Errorf("unable to find metadata address: %w", err)
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidAddress, err)
 	}
-	// Get account info using derived mdAddr
-	acc, err := sr.rpcClient.GetAccountInfo(context.Background(), mdAddr)
+	largest, err := sr.rpcClient.GetTokenLargestAccounts(ctx, mint, solanarpc.CommitmentConfirmed)
 	if err != nil {
-		return []string{}, fmt.Errorf("unable to find account info: %w", err)
+		return nil, fmt.Errorf("error fetching largest accounts: %w", err)
 	}
-	// acc = &{{{333854140}} 0xc0000ac600}
-
-	/* Transformation */
-	data := acc.Value.Data.GetBinary() // get binary representation
-	// data = [4 6 197 193 206 99 141 37 103 210 100 104 176 94 185 81 209 162 141 204 110 18...]
-
-	var metadata token_metadata.Metadata
-	// Deserialize binary data, loading into metadata variable
-	decoder := bin.NewBorshDecoder(data)
-	if err := metadata.UnmarshalWithDecoder(decoder); err != nil {
-		return []string{}, fmt.Errorf("unable to deserialize data: %w", err)
+	if len(largest.Value) == 0 {
+		return []domain.TokenHolder{}, nil
 	}
-	// metadata = {
-	//	MetadataV1 TSLvdd1pWpHVjahSpsvCXUbgwsL3JAcvokwaKt1eokM 6yjNqPzTSanBWSa6dxVEgTjePXBrZ2FoHLDQwYwEsyM6
-	//	{Solana SOL https://cf-ipfs.com/ipfs/QmTXTMc25MJk6h7JmDQpXEFUF8aMgTzovM7915x6fyJu1m 0 <nil>}
-	// 	false false 0xc000013d40 Fungible <nil> <nil>
-	//	}
 
-	r, _ := regexp.Compile(`\x00+`) // remove null bytes
-	name := r.ReplaceAllString(metadata.Data.Name, "")
-	symbol := r.ReplaceAllString(metadata.Data.Symbol, "")
+	accounts := make([]solanago.PublicKey, len(largest.Value))
+	for i, account := range largest.Value {
+		accounts[i] = account.Address
+	}
+	out, err := sr.rpcClient.GetMultipleAccountsWithOpts(ctx, accounts,
+		&solanarpc.GetMultipleAccountsOpts{Commitment: solanarpc.CommitmentConfirmed, Encoding: solanago.EncodingJSONParsed},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching holder accounts: %w", err)
+	}
 
-	return []string{name, symbol}, nil
-	// ["Solana", "SOL"]
+	holders := make([]domain.TokenHolder, 0, len(largest.Value))
+	for i, account := range largest.Value {
+		holder := domain.TokenHolder{Account: account.Address.String()}
+		if account.UiAmount != nil {
+			holder.Amount = *account.UiAmount
+		}
+		if i < len(out.Value) && out.Value[i] != nil {
+			// parsed.info: {mint, owner, tokenAmount}
+			owner := gjson.GetBytes(out.Value[i].Data.GetRawJSON(), "parsed.info.owner").String()
+			if key, err := solanago.PublicKeyFromBase58(owner); err == nil {
+				holder.Owner = owner
+				holder.ProgramOwned = !key.IsOnCurve()
+			}
+		}
+		holders = append(holders, holder)
+	}
+	return holders, nil
 }
 
+// signature history page size, and the most pages walked before giving up on an address
+const (
+	signaturePageSize = 1000
+	maxSignaturePages = 500
+)
+
 // `GetTokenAge` determines when a token is created by finding its earliest transaction
-// involving the metadata account
+// involving the metadata account, falling back to the mint account for tokens without Metaplex metadata
 // returns creation time as UTC timestamp.
 func (sr *solanaTokenRepo) GetTokenAge(ctx context.Context, tokenAddress string) (time.Time, error) {
-	mint := solanago.MustPublicKeyFromBase58(tokenAddress)
+	mint, err := solanago.PublicKeyFromBase58(tokenAddress)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %v", domain.ErrInvalidAddress, err)
+	}
 
 	// find where metadata is stored
 	// using token mint, and token programID
@@ -159,11 +205,46 @@ func (sr *solanaTokenRepo) GetTokenAge(ctx context.Context, tokenAddress string)
 	if err != nil {
 		return time.Time{}, fmt.Errorf("unable to find metadata address: %w", err)
 	}
-	sig, err := sr.rpcClient.GetSignaturesForAddress(ctx, mdAddr)
-	if err != nil {
-		return time.Time{}, fmt.Errorf("unable to find account info: %w", err)
+
+	for _, address := range []solanago.PublicKey{mdAddr, mint} {
+		sig, err := sr.oldestSignature(ctx, address)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if sig == nil {
+			continue
+		}
+		if sig.BlockTime == nil {
+			return time.Time{}, fmt.Errorf("no block time for signature %s", sig.Signature)
+		}
+		return sig.BlockTime.Time().UTC(), nil
 	}
-	time := sig[len(sig)-1].BlockTime.Time().UTC()
+	return time.Time{}, fmt.Errorf("%w: no signatures for %s", domain.ErrTokenNotFound, tokenAddress)
+}
 
-	return time, nil
+// `oldestSignature` walks the signature history of address back to its first transaction
+// using before cursors. Returns nil when the address has no history.
+func (sr *solanaTokenRepo) oldestSignature(ctx context.Context, address solanago.PublicKey) (*solanarpc.TransactionSignature, error) {
+	var (
+		oldest *solanarpc.TransactionSignature
+		before solanago.Signature
+		limit  = signaturePageSize
+	)
+	for range maxSignaturePages {
+		page, err := sr.rpcClient.GetSignaturesForAddressWithOpts(ctx, address,
+			&solanarpc.GetSignaturesForAddressOpts{Limit: &limit, Before: before, Commitment: solanarpc.CommitmentFinalized},
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching signatures for %s: %w", address, err)
+		}
+		if len(page) == 0 {
+			return oldest, nil
+		}
+		oldest = page[len(page)-1]
+		if len(page) < signaturePageSize {
+			return oldest, nil
+		}
+		before = oldest.Signature
+	}
+	return nil, fmt.Errorf("signature history of %s exceeds %d pages", address, maxSignaturePages)
 }
@@ -0,0 +1,253 @@
+// Package `solana` provides implementations of repository interfaces using Solana RPC methods,
+// and external API calls
+package solana
+
+import (
+	"math"
+	"slices"
+
+	"github.com/jakobsym/aura/internal/domain"
+)
+
+// SOL changes at or below this are treated as rent / fees rather than deposits or transfers
+const solDustThreshold = 0.01
+
+// `ClassifyTxn` labels a txn touching walletAddress with what it did to the wallet
+// attaching the typed payload matching the label.
+// Checks run from most to least specific: stake, mint, NFT, liquidity, burn, swap, transfer.
+func (sr *solanaWebSocketRepo) ClassifyTxn(payload domain.TransactionResult, walletAddress string) (domain.TxnEvent, error) {
+	unknown := domain.TxnEvent{Type: domain.TxnUnknown}
+	if payload.Result.Meta.Err != nil {
+		return unknown, nil
+	}
+
+	legs := walletNetChanges(payload, walletAddress)
+	tokenLegs := walletTokenChanges(payload, walletAddress)
+	solDelta := walletLamportDelta(payload, walletAddress)
+	tokenIxs := tokenInstructions(payload)
+	venue, router := identifyVenue(payload)
+	programs := invokedPrograms(payload)
+	tokenSent, tokenReceived := splitLegs(tokenLegs)
+
+	// stake, SOL in / out of a stake program without token balance changes
+	// other than a liquid staking token minted or burned directly (not routed by an aggregator).
+	// Txns otherwise moving tokens through a stake program (i.e: swaps routed via LSTs) fall through
+	routed := slices.ContainsFunc(programs, func(programId string) bool {
+		info, ok := lookupProgram(programId)
+		return ok && info.aggregator
+	})
+	lstMinted := !routed && len(tokenLegs) == 1 &&
+		(tokenIxs["MintTo"] || tokenIxs["MintToChecked"] || tokenIxs["Burn"] || tokenIxs["BurnChecked"])
+	for _, programId := range programs {
+		name, ok := stakePrograms[programId]
+		if !ok || (len(tokenLegs) > 0 && !lstMinted) {
+			continue
+		}
+		event := domain.TxnEvent{Type: domain.TxnStake, Stake: &domain.StakePayload{Program: name, Amount: solDelta}}
+		if lstMinted {
+			token := sr.tokenAmount(tokenLegs[0])
+			event.Stake.Token = &token
+		}
+		return event, nil
+	}
+
+	// token mint, outside of a venue where minting is a by-product of the trade
+	if venue == "" && (tokenIxs["MintTo"] || tokenIxs["MintToChecked"]) && len(tokenReceived) > 0 {
+		token := sr.tokenAmount(tokenReceived[0])
+		return domain.TxnEvent{Type: domain.TxnMint, Mint: &token}, nil
+	}
+
+	// NFT buy / sell settled in SOL
+	if event, ok := nftEvent(payload, legs, programs); ok {
+		return event, nil
+	}
+
+	// liquidity add / remove
+	if venue != "" && venue != domain.VenuePumpFun && router == "" {
+		lpLegs := slices.Clone(tokenLegs)
+		if math.Abs(solDelta) > solDustThreshold {
+			lpLegs = append(lpLegs, domain.SwapLeg{Address: domain.WrappedSOLMint, Amount: solDelta})
+		}
+		lpSent, lpReceived := splitLegs(lpLegs)
+		minted := tokenIxs["MintTo"] || tokenIxs["MintToChecked"]
+		burned := tokenIxs["Burn"] || tokenIxs["BurnChecked"]
+
+		switch {
+		case minted && len(lpReceived) == 1 && len(lpSent) > 0:
+			return sr.liquidityEvent(domain.TxnLPAdd, venue, lpSent, &lpReceived[0]), nil
+		case burned && len(lpSent) == 1 && len(lpReceived) > 0:
+			return sr.liquidityEvent(domain.TxnLPRemove, venue, lpReceived, &lpSent[0]), nil
+		case len(lpSent) > 1 && len(lpReceived) == 0:
+			return sr.liquidityEvent(domain.TxnLPAdd, venue, lpSent, nil), nil
+		case len(lpReceived) > 1 && len(lpSent) == 0:
+			return sr.liquidityEvent(domain.TxnLPRemove, venue, lpReceived, nil), nil
+		}
+	}
+
+	// token burn, outside of a venue
+	if venue == "" && (tokenIxs["Burn"] || tokenIxs["BurnChecked"]) && len(tokenSent) > 0 {
+		token := sr.tokenAmount(tokenSent[0])
+		token.Amount = -token.Amount
+		return domain.TxnEvent{Type: domain.TxnBurn, Burn: &token}, nil
+	}
+
+	// swap
+	sent, received := splitLegs(legs)
+	if len(sent) > 0 && len(received) > 0 {
+		swaps, err := sr.GetTxnSwapData(payload, walletAddress)
+		if err != nil {
+			return unknown, err
+		}
+		if len(swaps) > 0 {
+			return domain.TxnEvent{Type: domain.TxnSwap, Swap: &swaps[0]}, nil
+		}
+	}
+
+	// SPL transfer
+	if len(tokenLegs) == 1 {
+		token := sr.tokenAmount(tokenLegs[0])
+		return transferEvent(token, tokenCounterparty(payload, walletAddress, token.Address, token.Amount)), nil
+	}
+
+	// SOL transfer, lamport changes within solDustThreshold are fees / rent
+	if len(tokenLegs) == 0 && math.Abs(solDelta) > solDustThreshold {
+		token := domain.TokenAmount{Address: domain.WrappedSOLMint, Symbol: nativeSolSymbol, Amount: solDelta}
+		return transferEvent(token, solCounterparty(payload, walletAddress, solDelta)), nil
+	}
+
+	return unknown, nil
+}
+
+// `splitLegs` separates net balance changes into sent (negative) and received (positive) legs
+func splitLegs(legs []domain.SwapLeg) (sent, received []domain.SwapLeg) {
+	for _, leg := range legs {
+		if leg.Amount < 0 {
+			sent = append(sent, leg)
+		} else if leg.Amount > 0 {
+			received = append(received, leg)
+		}
+	}
+	return sent, received
+}
+
+// `tokenAmount` converts a net balance change into a TokenAmount with its symbol resolved
+func (sr *solanaWebSocketRepo) tokenAmount(leg domain.SwapLeg) domain.TokenAmount {
+	return domain.TokenAmount{Address: leg.Address, Symbol: sr.swapLegSymbol(leg.Address), Amount: leg.Amount}
+}
+
+// `liquidityEvent` builds an LP add / remove event
+// tokens are reported as positive amounts deposited or withdrawn
+func (sr *solanaWebSocketRepo) liquidityEvent(txnType domain.TxnType, venue string, tokens []domain.SwapLeg, lp *domain.SwapLeg) domain.TxnEvent {
+	payload := &domain.LiquidityPayload{Venue: venue}
+	for _, leg := range tokens {
+		token := sr.tokenAmount(leg)
+		token.Amount = math.Abs(token.Amount)
+		payload.Tokens = append(payload.Tokens, token)
+	}
+	if lp != nil {
+		token := sr.tokenAmount(*lp)
+		token.Amount = math.Abs(token.Amount)
+		payload.LP = &token
+	}
+	return domain.TxnEvent{Type: txnType, Liquidity: payload}
+}
+
+// `nftEvent` detects a single NFT (0 decimals, amount 1) exchanged against SOL
+func nftEvent(payload domain.TransactionResult, legs []domain.SwapLeg, programs []string) (domain.TxnEvent, bool) {
+	decimals := mintDecimals(payload)
+
+	var nft, sol *domain.SwapLeg
+	for i, leg := range legs {
+		switch {
+		case leg.Address == domain.WrappedSOLMint:
+			sol = &legs[i]
+		case decimals[leg.Address] == 0 && math.Abs(leg.Amount) == 1:
+			if nft != nil {
+				return domain.TxnEvent{}, false
+			}
+			nft = &legs[i]
+		}
+	}
+	if nft == nil || sol == nil || (nft.Amount > 0) == (sol.Amount > 0) {
+		return domain.TxnEvent{}, false
+	}
+
+	event := domain.TxnEvent{Type: domain.TxnNFTBuy, NFT: &domain.NFTPayload{Address: nft.Address, Price: math.Abs(sol.Amount)}}
+	if nft.Amount < 0 {
+		event.Type = domain.TxnNFTSell
+	}
+	for _, programId := range programs {
+		if name, ok := nftMarketplaces[programId]; ok {
+			event.NFT.Marketplace = name
+			break
+		}
+	}
+	return event, true
+}
+
+// `transferEvent` builds a transfer in / out event from a signed token amount
+func transferEvent(token domain.TokenAmount, counterparty string) domain.TxnEvent {
+	txnType := domain.TxnTransferIn
+	if token.Amount < 0 {
+		txnType = domain.TxnTransferOut
+		token.Amount = -token.Amount
+	}
+	return domain.TxnEvent{Type: txnType, Transfer: &domain.TransferPayload{TokenAmount: token, Counterparty: counterparty}}
+}
+
+// `mintDecimals` maps every mint in a txn's token balances to its decimals
+func mintDecimals(payload domain.TransactionResult) map[string]int {
+	decimals := make(map[string]int)
+	for _, balances := range [][]domain.TokenBalance{payload.Result.Meta.PreTokenBalances, payload.Result.Meta.PostTokenBalances} {
+		for _, balance := range balances {
+			decimals[balance.Mint] = balance.UITokenAmount.Decimals
+		}
+	}
+	return decimals
+}
+
+// `solCounterparty` returns the account whose lamport change most closely offsets the wallet's
+func solCounterparty(payload domain.TransactionResult, walletAddress string, delta float64) string {
+	var (
+		counterparty string
+		best         float64
+	)
+	for i, key := range resolveAccountKeys(payload) {
+		if key == walletAddress {
+			continue
+		}
+		// counterparty moves lamports in the opposite direction of the wallet
+		if opposite := -lamportDelta(payload, i) * math.Copysign(1, delta); opposite > best {
+			best = opposite
+			counterparty = key
+		}
+	}
+	return counterparty
+}
+
+// `tokenCounterparty` returns the owner whose balance of mint most closely offsets the wallet's
+func tokenCounterparty(payload domain.TransactionResult, walletAddress, mint string, delta float64) string {
+	owners := make(map[string]float64)
+	for _, pre := range payload.Result.Meta.PreTokenBalances {
+		if pre.Mint == mint && pre.Owner != walletAddress {
+			owners[pre.Owner] -= pre.UITokenAmount.UIAmount
+		}
+	}
+	for _, post := range payload.Result.Meta.PostTokenBalances {
+		if post.Mint == mint && post.Owner != walletAddress {
+			owners[post.Owner] += post.UITokenAmount.UIAmount
+		}
+	}
+
+	var (
+		counterparty string
+		best         float64
+	)
+	for owner, ownerDelta := range owners {
+		if opposite := -ownerDelta * math.Copysign(1, delta); opposite > best {
+			best = opposite
+			counterparty = owner
+		}
+	}
+	return counterparty
+}
@@ -0,0 +1,90 @@
+// Package `solana` provides implementations of repository interfaces using Solana RPC methods,
+// and external API calls
+package solana
+
+import (
+	"context"
+	"fmt"
+
+	solanago "github.com/gagliardetto/solana-go"
+	solanarpc "github.com/gagliardetto/solana-go/rpc"
+	"github.com/jakobsym/aura/internal/domain"
+	"github.com/jakobsym/aura/internal/repository"
+	"github.com/tidwall/gjson"
+)
+
+// token programs whose accounts make up a wallet's holdings
+var holdingPrograms = []struct {
+	name      string
+	programId solanago.PublicKey
+}{
+	{name: "spl-token", programId: solanago.TokenProgramID},
+	{name: "spl-token-2022", programId: solanago.Token2022ProgramID},
+}
+
+// `solanaWalletRepo` implements the repository.SolanaWalletRepo interface using a solanarpc.Client
+type solanaWalletRepo struct {
+	rpcClient *solanarpc.Client
+}
+
+// `NewSolanaWalletRepo` creates and returns a new solanarpc.Client implementation
+// of the SolanaWalletRepo interface.
+func NewSolanaWalletRepo(c *solanarpc.Client) repository.SolanaWalletRepo {
+	return &solanaWalletRepo{rpcClient: c}
+}
+
+// `GetSolBalance` retrieves the native SOL balance of a walletAddress
+func (wr *solanaWalletRepo) GetSolBalance(ctx context.Context, walletAddress string) (float64, error) {
+	owner, err := solanago.PublicKeyFromBase58(walletAddress)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", domain.ErrInvalidAddress, err)
+	}
+	out, err := wr.rpcClient.GetBalance(ctx, owner, solanarpc.CommitmentConfirmed)
+	if err != nil {
+		return 0, fmt.Errorf("error fetching balance: %w", err)
+	}
+	return float64(out.Value) / float64(solanago.LAMPORTS_PER_SOL), nil
+}
+
+// `GetTokenHoldings` retrieves every SPL Token and Token-2022 balance held by a walletAddress
+// balances held across several token accounts of the same mint are summed,
+// empty accounts are omitted
+func (wr *solanaWalletRepo) GetTokenHoldings(ctx context.Context, walletAddress string) ([]domain.TokenHolding, error) {
+	owner, err := solanago.PublicKeyFromBase58(walletAddress)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidAddress, err)
+	}
+
+	var holdings []domain.TokenHolding
+	byMint := make(map[string]int)
+	for _, program := range holdingPrograms {
+		out, err := wr.rpcClient.GetTokenAccountsByOwner(ctx, owner,
+			&solanarpc.GetTokenAccountsConfig{ProgramId: &program.programId},
+			&solanarpc.GetTokenAccountsOpts{Commitment: solanarpc.CommitmentConfirmed, Encoding: solanago.EncodingJSONParsed},
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching %s accounts: %w", program.name, err)
+		}
+		for _, account := range out.Value {
+			// parsed.info: {mint, owner, tokenAmount: {uiAmount, decimals}}
+			info := gjson.GetBytes(account.Account.Data.GetRawJSON(), "parsed.info")
+			mint := info.Get("mint").String()
+			amount := info.Get("tokenAmount.uiAmount").Float()
+			if mint == "" || amount <= 0 {
+				continue
+			}
+			if i, ok := byMint[mint]; ok {
+				holdings[i].Amount += amount
+				continue
+			}
+			byMint[mint] = len(holdings)
+			holdings = append(holdings, domain.TokenHolding{
+				Mint:     mint,
+				Program:  program.name,
+				Amount:   amount,
+				Decimals: int(info.Get("tokenAmount.decimals").Int()),
+			})
+		}
+	}
+	return holdings, nil
+}
@@ -7,18 +7,17 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"hash/fnv"
 	"io"
 	"log"
+	"math"
 	"net/http"
 	"os"
-	"regexp"
+	"slices"
 	"sync"
 	"time"
 
-	bin "github.com/gagliardetto/binary"
-	token_metadata "github.com/gagliardetto/metaplex-go/clients/token-metadata"
 	solanago "github.com/gagliardetto/solana-go"
-	solanarpc "github.com/gagliardetto/solana-go/rpc"
 	"github.com/gorilla/websocket"
 	"github.com/jakobsym/aura/internal/domain"
 	"github.com/jakobsym/aura/internal/repository"
@@ -28,11 +27,19 @@ import (
 // `solanaWebSocketRepo` implements SolanaWebSocketRepo interface
 // for interacting with real-time data via Helius RPC websockets
 type solanaWebSocketRepo struct {
-	Websocket *websocket.Conn
-	mu        sync.Mutex
-	pending   sync.Map                        // subscription responses
-	subs      []chan domain.HeliusLogResponse // active subscriptions
-	//Accounts  []string
+	ws       *websocket.Conn // swapped by reconnect, accessed under mu
+	mu       sync.Mutex
+	pending  sync.Map                         // subscription responses
+	subs     []chan domain.WalletNotification // active listeners
+	registry *subscriptionRegistry            // tracked wallets and their Helius subscriptions
+	metadata *TokenMetadataResolver           // resolves swap leg symbols
+	queues   []chan walletLog                 // log notifications awaiting their txn, one queue per worker
+}
+
+// `walletLog` is a log notification for a tracked wallet
+type walletLog struct {
+	walletAddress string
+	notification  domain.HeliusLogResponse
 }
 
 // websocket connection logic constants
@@ -41,52 +48,148 @@ const (
 	pingPeriod = 30 * time.Second
 	readWait   = 50 * time.Second
 	writeWait  = 10 * time.Second
+
+	reconnectBaseDelay = 1 * time.Second
+	reconnectMaxDelay  = 60 * time.Second
+)
+
+// notification processing constants
+const (
+	notificationWorkers   = 4   // a wallet's notifications always go to the same worker, keeping them in order
+	notificationQueueSize = 256 // notifications queued per worker before new ones are dropped
+)
+
+// `heliusClient` is shared by Helius JSON-RPC calls
+var heliusClient = &http.Client{Timeout: 15 * time.Second}
+
+// swap detection constants
+const (
+	nativeSolSymbol  = "SOL" // symbol reported for native SOL swap legs
+	netZeroTolerance = 1e-9  // net balance changes at or below this are treated as zero
 )
 
 // `NewSolanaWebSocketRepo` creates a new Solana websocket repository intstance
-func NewSolanaWebSocketRepo(ws *websocket.Conn) repository.SolanaWebSocketRepo {
-	return &solanaWebSocketRepo{Websocket: ws, mu: sync.Mutex{}}
+func NewSolanaWebSocketRepo(ws *websocket.Conn, mr *TokenMetadataResolver) repository.SolanaWebSocketRepo {
+	queues := make([]chan walletLog, notificationWorkers)
+	for i := range queues {
+		queues[i] = make(chan walletLog, notificationQueueSize)
+	}
+	return &solanaWebSocketRepo{ws: ws, mu: sync.Mutex{}, registry: newSubscriptionRegistry(), metadata: mr, queues: queues}
 }
 
 // `SolanaWebSocketConnection` establishes a websocket connection to a Helius RPC endpoint
 // Configures a pong handler to establish ping/pong connection between websocket and local server.
 // returns the connection
 func SolanaWebSocketConnection() *websocket.Conn {
+	ws, err := dialWebSocket()
+	if err != nil {
+		log.Fatalf("unable to create ws connection: %v", err)
+	}
+	log.Println("WebSocket conneciton established.")
+	return ws
+}
+
+// `dialWebSocket` dials the Helius websocket endpoint and configures its pong handler
+func dialWebSocket() (*websocket.Conn, error) {
 	url := fmt.Sprintf("wss://mainnet.helius-rpc.com/?api-key=%s", os.Getenv("HELIUS_API_KEY"))
 	ws, _, err := websocket.DefaultDialer.Dial(url, nil)
 	if err != nil {
-		log.Fatalf("unable to create ws connection: %v", err)
+		return nil, err
 	}
 	ws.SetPongHandler(func(string) error {
 		ws.SetReadDeadline(time.Now().Add(pongWait))
 		return nil
 	})
-	log.Println("WebSocket conneciton established.")
-	return ws
+	return ws, nil
+}
+
+// `reconnect` closes the current websocket and redials Helius using exponential backoff
+// Blocks until a new connection is established or the context is cancelled.
+func (sr *solanaWebSocketRepo) reconnect(ctx context.Context) error {
+	sr.mu.Lock()
+	sr.ws.Close()
+	sr.mu.Unlock()
+
+	delay := reconnectBaseDelay
+	for {
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		ws, err := dialWebSocket()
+		if err != nil {
+			log.Printf("websocket reconnect failed, retrying in %s: %v", delay, err)
+			delay = min(delay*2, reconnectMaxDelay)
+			continue
+		}
+		sr.mu.Lock()
+		sr.ws = ws
+		sr.mu.Unlock()
+		log.Println("WebSocket connection re-established.")
+		return nil
+	}
+}
+
+// `resubscribe` re-issues logsSubscribe for every tracked wallet after a reconnect
+// the old Helius subscription IDs are dropped, as they died with the previous connection.
+func (sr *solanaWebSocketRepo) resubscribe(ctx context.Context) {
+	sr.registry.resetSubscriptions()
+	sr.retrySubscribe(ctx, sr.registry.trackedWallets())
+}
+
+// `retrySubscribe` subscribes to the logs of wallets left without a subscription
+// retrying failures with exponential backoff until every wallet is subscribed, untracked, or ctx is done.
+func (sr *solanaWebSocketRepo) retrySubscribe(ctx context.Context, wallets []string) {
+	delay := reconnectBaseDelay
+	for len(wallets) > 0 {
+		var failed []string
+		for _, walletAddress := range wallets {
+			// skip wallets untracked, or subscribed by another caller meanwhile
+			if !sr.registry.beginSubscribe(walletAddress) {
+				continue
+			}
+			if err := sr.logsSubscribe(ctx, walletAddress); err != nil {
+				log.Printf("failed to subscribe %s, retrying in %s: %v", walletAddress, delay, err)
+				failed = append(failed, walletAddress)
+			}
+		}
+		if len(failed) == 0 {
+			return
+		}
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return
+		}
+		delay = min(delay*2, reconnectMaxDelay)
+		wallets = failed
+	}
 }
 
 // `HandleWebSocketConnection` manages websocket connection by implementing
 // a ping/pong mechanism to keep the connection alive
 func (sr *solanaWebSocketRepo) HandleWebSocketConnection(ctx context.Context) {
-	pingTicker := time.NewTicker(pingPeriod)
-	defer pingTicker.Stop()
-
 	go func() {
+		pingTicker := time.NewTicker(pingPeriod)
+		defer pingTicker.Stop()
+
 		for {
 			select {
 			case <-pingTicker.C:
 				sr.mu.Lock()
 				// write deadling for ping
-				if err := sr.Websocket.SetWriteDeadline(time.Now().Add(writeWait)); err != nil {
+				if err := sr.ws.SetWriteDeadline(time.Now().Add(writeWait)); err != nil {
 					sr.mu.Unlock()
 					continue
 				}
 				// send ping
-				if err := sr.Websocket.WriteMessage(websocket.PingMessage, nil); err != nil {
+				if err := sr.ws.WriteMessage(websocket.PingMessage, nil); err != nil {
 					log.Printf("failed to send ping: %v", err)
 				} else {
 					log.Println("Ping sent")
-					sr.Websocket.SetReadDeadline(time.Now().Add(pongWait))
+					sr.ws.SetReadDeadline(time.Now().Add(pongWait))
 				}
 				sr.mu.Unlock()
 			case <-ctx.Done():
@@ -98,72 +201,163 @@ func (sr *solanaWebSocketRepo) HandleWebSocketConnection(ctx context.Context) {
 
 // `StartReader` continuously reads messages from the websocket
 // processing and dispatching them to the appropriate handlers.
-// Handles subscription responses, log notifications, and
-// extracts txn data when available.
+// Handles subscription responses, and queues log notifications for
+// notificationWorkers to extract their txn data, keeping slow RPC calls off the reader.
+// On a read failure the connection is redialed and tracked wallets are resubscribed.
 func (sr *solanaWebSocketRepo) StartReader(ctx context.Context) {
+	for _, queue := range sr.queues {
+		go sr.processLogs(ctx, queue)
+	}
 	go func() {
-		sr.Websocket.SetPongHandler(func(string) error {
-			log.Println("Received pong from server")
-			sr.Websocket.SetReadDeadline(time.Now().Add(pongWait))
-			return nil
-		})
-
 		for {
-			// read raw message
-			var rawRes json.RawMessage
-			if err := sr.Websocket.ReadJSON(&rawRes); err != nil {
-				log.Printf("Websocket read error: %v", err)
+			ws := sr.conn()
+			ws.SetPongHandler(func(string) error {
+				log.Println("Received pong from server")
+				ws.SetReadDeadline(time.Now().Add(pongWait))
+				return nil
+			})
+			sr.readMessages(ws)
+
+			if err := sr.reconnect(ctx); err != nil {
+				log.Printf("Websocket reconnect aborted: %v", err)
 				return
 			}
+			go sr.resubscribe(ctx)
+		}
+	}()
+}
 
-			// try accountSubscribe() response
-			var accountSubscribeRes domain.HeliusSubscriptionResponse
-			if err := json.Unmarshal(rawRes, &accountSubscribeRes); err == nil && accountSubscribeRes.ID != 0 {
-				if ch, ok := sr.pending.Load(accountSubscribeRes.ID); ok {
-					ch.(chan domain.HeliusSubscriptionResponse) <- accountSubscribeRes
-					sr.pending.Delete(accountSubscribeRes.ID)
-				}
-				continue
-			}
+// `conn` returns the current websocket connection
+// read under mu as reconnect swaps it
+func (sr *solanaWebSocketRepo) conn() *websocket.Conn {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+	return sr.ws
+}
 
-			// try logResponse
-			var logResponse domain.HeliusLogResponse
-			if err := json.Unmarshal([]byte(rawRes), &logResponse); err != nil && logResponse.Method == "logsNotification" {
-				sr.mu.Lock()
-				for _, sub := range sr.subs {
-					select {
-					case sub <- logResponse:
-					default:
-						log.Println("Sub channel full, dropping notification")
-					}
-				}
-				sr.mu.Unlock()
-				continue
-			}
-			// process txn data
-			txnSignature := logResponse.Params.Result.Value.Signature
-			payload, err := sr.GetTxnData(txnSignature)
-			if err != nil {
-				log.Printf("Error getting txn details from signature: %v", err)
-				continue
-			}
-			swapData, err := sr.GetTxnSwapData(payload)
-			if err != nil {
-				log.Printf("Error getting txn swap data from payload: %v", err)
+// `readMessages` reads from a single websocket connection until a read error occurs
+func (sr *solanaWebSocketRepo) readMessages(ws *websocket.Conn) {
+	for {
+		// read raw message
+		var rawRes json.RawMessage
+		if err := ws.ReadJSON(&rawRes); err != nil {
+			log.Printf("Websocket read error: %v", err)
+			return
+		}
+
+		// try subscription response
+		var subscribeRes domain.HeliusSubscriptionResponse
+		if err := json.Unmarshal(rawRes, &subscribeRes); err == nil && subscribeRes.ID != 0 {
+			if ch, ok := sr.pending.Load(subscribeRes.ID); ok {
+				ch.(chan domain.HeliusSubscriptionResponse) <- subscribeRes
+				sr.pending.Delete(subscribeRes.ID)
 			}
-			log.Println(swapData)
+			continue
 		}
-	}()
+
+		// try logResponse
+		var logResponse domain.HeliusLogResponse
+		if err := json.Unmarshal(rawRes, &logResponse); err != nil || logResponse.Method != "logsNotification" {
+			continue
+		}
+		walletAddress, ok := sr.registry.walletFor(logResponse.Params.Subscription)
+		if !ok {
+			log.Printf("Notification for unknown subscription ID: %d", logResponse.Params.Subscription)
+			continue
+		}
+		sr.enqueue(walletLog{walletAddress: walletAddress, notification: logResponse})
+	}
 }
 
-// `LogsSubscribe` subscribes to logs for a specific wallet address
-// sends a subscription request and awaits for confirmation.
-func (sr *solanaWebSocketRepo) LogsSubscribe(ctx context.Context, walletAddress string, userId int) error {
+// `enqueue` hands a log notification to the worker owning its wallet
+// dropping it when the worker has fallen notificationQueueSize notifications behind
+func (sr *solanaWebSocketRepo) enqueue(wl walletLog) {
+	h := fnv.New32a()
+	h.Write([]byte(wl.walletAddress))
+	select {
+	case sr.queues[h.Sum32()%uint32(len(sr.queues))] <- wl:
+	default:
+		log.Printf("Notification queue full, dropping txn %s for %s", wl.notification.Params.Result.Value.Signature, wl.walletAddress)
+	}
+}
+
+// `processLogs` fetches, classifies and dispatches the txns of queued log notifications until ctx is done
+func (sr *solanaWebSocketRepo) processLogs(ctx context.Context, queue <-chan walletLog) {
+	for {
+		select {
+		case wl := <-queue:
+			sr.processLog(wl)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// `processLog` fetches the txn behind a log notification, classifies it for the wallet and dispatches the event
+func (sr *solanaWebSocketRepo) processLog(wl walletLog) {
+	walletAddress := wl.walletAddress
+	txnSignature := wl.notification.Params.Result.Value.Signature
+	payload, err := sr.GetTxnData(txnSignature)
+	if err != nil {
+		log.Printf("Error getting txn details from signature: %v", err)
+		return
+	}
+	// fall back to the logs carried by the notification for venue detection
+	if len(payload.Result.Meta.LogMessages) == 0 {
+		payload.Result.Meta.LogMessages = wl.notification.Params.Result.Value.Logs
+	}
+	event, err := sr.ClassifyTxn(payload, walletAddress)
+	if err != nil {
+		log.Printf("Error classifying txn from payload: %v", err)
+		return
+	}
+	if event.Type == domain.TxnUnknown {
+		log.Printf("Unclassified txn for %s: %s", walletAddress, txnSignature)
+		return
+	}
+	sr.dispatch(domain.WalletNotification{
+		WalletAddress: walletAddress,
+		Signature:     txnSignature,
+		Slot:          payload.Result.Slot,
+		BlockTime:     time.Unix(payload.Result.BlockTime, 0).UTC(),
+		Event:         event,
+	})
+}
+
+// `dispatch` pushes a decoded wallet event to every active listener
+func (sr *solanaWebSocketRepo) dispatch(notification domain.WalletNotification) {
 	sr.mu.Lock()
 	defer sr.mu.Unlock()
+	for _, sub := range sr.subs {
+		select {
+		case sub <- notification:
+		default:
+			log.Println("Sub channel full, dropping notification")
+		}
+	}
+}
+
+// `LogsSubscribe` registers userId as tracking walletAddress and subscribes to the wallet's logs
+// wallets that already hold a Helius subscription are not subscribed twice.
+// A failed subscribe is retried in the background.
+func (sr *solanaWebSocketRepo) LogsSubscribe(ctx context.Context, walletAddress string, userId int) error {
+	if !sr.registry.addUser(walletAddress, userId) {
+		return nil
+	}
+	if err := sr.logsSubscribe(ctx, walletAddress); err != nil {
+		go sr.retrySubscribe(context.WithoutCancel(ctx), []string{walletAddress})
+		return err
+	}
+	return nil
+}
+
+// `logsSubscribe` sends a logsSubscribe request and awaits for confirmation.
+// The returned subscription ID is recorded in the registry.
+func (sr *solanaWebSocketRepo) logsSubscribe(ctx context.Context, walletAddress string) error {
+	requestId := sr.registry.addRequest(walletAddress)
 	msg := domain.HeliusRequest{
 		JsonRPC: "2.0",
-		ID:      userId,
+		ID:      requestId,
 		Method:  "logsSubscribe",
 		Params: []any{
 			domain.LogsSubscribeParams{
@@ -174,30 +368,92 @@ func (sr *solanaWebSocketRepo) LogsSubscribe(ctx context.Context, walletAddress
 			},
 		},
 	}
+	res, err := sr.sendRequest(ctx, msg)
+	if err != nil {
+		sr.registry.failRequest(requestId)
+		return fmt.Errorf("logsSubscribe failed: %w", err)
+	}
+	var subscriptionId int
+	if err := json.Unmarshal(res.Result, &subscriptionId); err != nil {
+		sr.registry.failRequest(requestId)
+		return fmt.Errorf("invalid subscription ID: %w", err)
+	}
+
+	if previous := sr.registry.resolveRequest(requestId, subscriptionId); previous != 0 {
+		log.Printf("Resubscribed to %s | ID: %d -> %d\n", walletAddress, previous, subscriptionId)
+		return nil
+	}
+	log.Printf("Subscribed to %s | ID: %d\n", walletAddress, subscriptionId)
+	return nil
+}
+
+// `LogsUnsubscribe` drops userId from walletAddress. When stillTracked, as reported by the database,
+// is false its log subscription is cancelled, and the wallet dropped from the registry once Helius confirms it.
+func (sr *solanaWebSocketRepo) LogsUnsubscribe(ctx context.Context, walletAddress string, userId int, stillTracked bool) error {
+	if stillTracked {
+		sr.registry.removeUser(walletAddress, userId)
+		return nil
+	}
+	// the database is the source of truth, users the registry still holds no longer track the wallet
+	sr.registry.removeUsers(walletAddress)
+	subscriptionId, ok := sr.registry.subscriptionFor(walletAddress)
+	if !ok {
+		// no live subscription to cancel
+		sr.registry.removeWallet(walletAddress, 0)
+		return nil
+	}
+	msg := domain.HeliusRequest{
+		JsonRPC: "2.0",
+		ID:      sr.registry.nextRequestID(),
+		Method:  "logsUnsubscribe",
+		Params:  []any{subscriptionId},
+	}
+
+	res, err := sr.sendRequest(ctx, msg)
+	if err != nil {
+		return fmt.Errorf("logsUnsubscribe failed: %w", err)
+	}
+	var unsubscribed bool
+	if err := json.Unmarshal(res.Result, &unsubscribed); err != nil || !unsubscribed {
+		return fmt.Errorf("unsubscribe rejected for subscription ID: %d", subscriptionId)
+	}
+	log.Printf("Unsubscribed from %s | ID: %d\n", walletAddress, subscriptionId)
+	if !sr.registry.removeWallet(walletAddress, subscriptionId) {
+		// tracked again while the unsubscribe was in flight
+		go sr.retrySubscribe(context.WithoutCancel(ctx), []string{walletAddress})
+	}
+	return nil
+}
+
+// `sendRequest` writes a JSON-RPC request to the websocket and awaits its response
+// which StartReader routes back through the pending map.
+func (sr *solanaWebSocketRepo) sendRequest(ctx context.Context, msg domain.HeliusRequest) (domain.HeliusSubscriptionResponse, error) {
 	// create channel for response and store in pending map
 	responseCh := make(chan domain.HeliusSubscriptionResponse, 1)
 	sr.pending.Store(msg.ID, responseCh)
 	defer sr.pending.Delete(msg.ID)
 
-	// send sub request
-	if err := sr.Websocket.WriteJSON(msg); err != nil {
-		return fmt.Errorf("failed to send logsSubscription request: %w", err)
+	// send request
+	sr.mu.Lock()
+	err := sr.ws.WriteJSON(msg)
+	sr.mu.Unlock()
+	if err != nil {
+		return domain.HeliusSubscriptionResponse{}, fmt.Errorf("failed to send %s request: %w", msg.Method, err)
 	}
 
 	// await for response w/ timeout
 	select {
 	case res := <-responseCh:
 		if res.Error != nil {
-			return fmt.Errorf("subscription error: %v", res.Error.Message)
+			return domain.HeliusSubscriptionResponse{}, fmt.Errorf("%s error: %v", msg.Method, res.Error.Message)
 		}
-		log.Printf("Subscribed to %s | ID: %d\n", walletAddress, res.Result)
-		return nil
+		return res, nil
 
 	case <-time.After(30 * time.Second):
-		return fmt.Errorf("subscription timeout")
+		return domain.HeliusSubscriptionResponse{}, fmt.Errorf("%s timeout", msg.Method)
 
 	case <-ctx.Done():
-		return fmt.Errorf("context cancelled while awaiting subscription response")
+		return domain.HeliusSubscriptionResponse{}, fmt.Errorf("context cancelled while awaiting %s response", msg.Method)
 	}
 }
 
@@ -217,45 +473,144 @@ func (sr *solanaWebSocketRepo) GetTxnData(signature string) (domain.TransactionR
 		},
 	}
 
+	var payload domain.TransactionResult
+	if err := heliusRPC(msg, &payload); err != nil {
+		return domain.TransactionResult{}, err
+	}
+	return payload, nil
+}
+
+// `GetSignaturesForAddress` retrieves up to limit txn signatures involving an address, newest first
+// paging backwards from the before signature when given
+func (sr *solanaWebSocketRepo) GetSignaturesForAddress(address, before string, limit int) ([]domain.SignatureInfo, error) {
+	config := map[string]any{"limit": limit}
+	if before != "" {
+		config["before"] = before
+	}
+	msg := domain.HeliusRequest{
+		JsonRPC: "2.0",
+		ID:      1,
+		Method:  "getSignaturesForAddress",
+		Params:  []any{address, config},
+	}
+
+	var payload domain.SignaturesResult
+	if err := heliusRPC(msg, &payload); err != nil {
+		return nil, err
+	}
+	return payload.Result, nil
+}
+
+// `heliusRPC` POSTs a JSON-RPC request to the Helius RPC endpoint
+// and decodes the response body into out, giving up after heliusClient's timeout
+func heliusRPC(msg domain.HeliusRequest, out any) error {
 	// send request
 	reqMsg, err := json.Marshal(msg)
 	if err != nil {
-		return domain.TransactionResult{}, err
+		return err
 	}
 	url := fmt.Sprintf("https://mainnet.helius-rpc.com/?api-key=%s", os.Getenv("HELIUS_API_KEY"))
 	req, err := http.NewRequest("POST", url, bytes.NewBuffer(reqMsg))
 	if err != nil {
-		return domain.TransactionResult{}, err
+		return err
 	}
 	req.Header.Set("Content-Type", "application/json")
 
 	// process data
-	client := &http.Client{}
-	res, err := client.Do(req)
+	res, err := heliusClient.Do(req)
 	if err != nil {
-		return domain.TransactionResult{}, err
+		return err
 	}
 	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s request failed with status: %d", msg.Method, res.StatusCode)
+	}
 
-	var payload domain.TransactionResult
 	body, err := io.ReadAll(res.Body)
 	if err != nil {
-		return domain.TransactionResult{}, err
+		return err
 	}
-	if err := json.Unmarshal(body, &payload); err != nil {
-		return domain.TransactionResult{}, err
+	return json.Unmarshal(body, out)
+}
+
+// `GetTxnSwapData` analyzes txn data to identify token swaps
+// computing the tracked wallet's net balance change per mint across the whole txn.
+// Intermediate route hops that net to zero are dropped, so a multi-hop route
+// is reported as a single trade; trades touching more than one mint per side
+// list every leg explicitly.
+// Native SOL moved as lamports is treated as a wSOL leg of the swap.
+// The executing venue is identified from the programs the txn invokes.
+func (sr *solanaWebSocketRepo) GetTxnSwapData(payload domain.TransactionResult, userWalletAddress string) ([]domain.SwapResult, error) {
+	legs := walletNetChanges(payload, userWalletAddress)
+
+	var sent, received []domain.SwapLeg
+	for _, leg := range legs {
+		leg.Symbol = sr.swapLegSymbol(leg.Address)
+		if leg.Amount < 0 {
+			sent = append(sent, leg)
+		} else {
+			received = append(received, leg)
+		}
+	}
+	if len(sent) == 0 || len(received) == 0 {
+		return []domain.SwapResult{}, nil
 	}
 
-	return payload, nil
+	swap := domain.SwapResult{
+		SentAmount:      -sent[0].Amount,
+		SentSymbol:      sent[0].Symbol,
+		SentAddress:     sent[0].Address,
+		ReceivedAddress: received[0].Address,
+		ReceivedAmount:  received[0].Amount,
+		ReceivedSymbol:  received[0].Symbol,
+	}
+	if len(sent) > 1 || len(received) > 1 {
+		swap.Legs = slices.Concat(sent, received)
+	}
+	swap.Venue, swap.Router = identifyVenue(payload)
+	return []domain.SwapResult{swap}, nil
 }
 
-// `GetTxnSwapData` analyzes txn data to identify token swaps
-// extracting details regarding sent/recieved tokens to determine
-// balance changes for a tracked wallet.
-func (sr *solanaWebSocketRepo) GetTxnSwapData(payload domain.TransactionResult) ([]domain.SwapResult, error) {
-	userWalletAddress := payload.Result.Transaction.Message.AccountKeys[0]
+// `walletNetChanges` returns the tracked wallet's net balance change per mint
+// Native lamports are folded into the wSOL mint only when the token changes are one sided
+// so rent and priority fees are not mistaken for a swap leg.
+func walletNetChanges(payload domain.TransactionResult, walletAddress string) []domain.SwapLeg {
+	legs := walletTokenChanges(payload, walletAddress)
+	var hasSent, hasReceived bool
+	for _, leg := range legs {
+		hasSent = hasSent || leg.Amount < 0
+		hasReceived = hasReceived || leg.Amount > 0
+	}
+	if hasSent == hasReceived {
+		return legs
+	}
+
+	solDelta := walletLamportDelta(payload, walletAddress)
+	if i := slices.IndexFunc(legs, func(leg domain.SwapLeg) bool { return leg.Address == domain.WrappedSOLMint }); i >= 0 {
+		legs[i].Amount += solDelta
+		if math.Abs(legs[i].Amount) <= netZeroTolerance {
+			legs = slices.Delete(legs, i, i+1)
+		}
+		return legs
+	}
+	if math.Abs(solDelta) > netZeroTolerance {
+		legs = append(legs, domain.SwapLeg{Address: domain.WrappedSOLMint, Amount: solDelta})
+	}
+	return legs
+}
+
+// `walletTokenChanges` sums the balance change of every token account owned by walletAddress
+// per mint, in order of first appearance, dropping mints that net to zero.
+func walletTokenChanges(payload domain.TransactionResult, walletAddress string) []domain.SwapLeg {
 	balanceMap := make(map[int]map[string]domain.TokenBalance)
-	var swaps []domain.SwapResult
+	net := make(map[string]float64)
+	var mints []string
+	recordDelta := func(mint string, delta float64) {
+		if _, ok := net[mint]; !ok {
+			mints = append(mints, mint)
+		}
+		net[mint] += delta
+	}
 
 	// build balanceMap
 	for _, pre := range payload.Result.Meta.PreTokenBalances {
@@ -265,106 +620,90 @@ func (sr *solanaWebSocketRepo) GetTxnSwapData(payload domain.TransactionResult)
 		balanceMap[pre.AccountIndex][pre.Mint] = pre
 	}
 
-	// process post balance i.e: find swaps
-	var sent, received []domain.TokenBalance
+	// process post balances
 	for _, post := range payload.Result.Meta.PostTokenBalances {
-		if post.Owner != userWalletAddress {
+		pre, exists := balanceMap[post.AccountIndex][post.Mint]
+		if exists {
+			delete(balanceMap[post.AccountIndex], post.Mint)
+		}
+		if post.Owner != walletAddress {
 			continue
 		}
 
-		pre, exists := balanceMap[post.AccountIndex][post.Mint]
-		if !exists {
+		// token accounts created within the txn have no pre balance
+		// i.e: first buy of a token, treated as a zero balance
+		recordDelta(post.Mint, post.UITokenAmount.UIAmount-pre.UITokenAmount.UIAmount)
+	}
+
+	// token accounts closed within the txn have no post balance
+	// i.e: full exit of a token, treated as a zero balance
+	for _, pre := range payload.Result.Meta.PreTokenBalances {
+		if _, open := balanceMap[pre.AccountIndex][pre.Mint]; !open || pre.Owner != walletAddress {
 			continue
 		}
+		recordDelta(pre.Mint, -pre.UITokenAmount.UIAmount)
+	}
 
-		// calculate token amount changes
-		delta := post.UITokenAmount.UIAmount - pre.UITokenAmount.UIAmount
-		if delta < 0 {
-			// token was sent
-			sent = append(sent, domain.TokenBalance{
-				Mint: post.Mint,
-				UITokenAmount: domain.UITokenAmount{
-					UIAmount: -delta,
-				},
-			})
-		} else if delta > 0 {
-			// token was received
-			received = append(received, domain.TokenBalance{
-				Mint: post.Mint,
-				UITokenAmount: domain.UITokenAmount{
-					UIAmount: delta,
-				},
-			})
+	legs := make([]domain.SwapLeg, 0, len(mints))
+	for _, mint := range mints {
+		if math.Abs(net[mint]) <= netZeroTolerance {
+			continue
 		}
+		legs = append(legs, domain.SwapLeg{Address: mint, Amount: net[mint]})
 	}
+	return legs
+}
 
-	// pair sent and received tokens to identify swaps
-	for i := 0; i < len(sent) && i < len(received); i++ {
-		// get metadata for sent/received tokens
-		sentTokenDetail, err := sr.GetTokenNameAndSymbol(context.TODO(), sent[i].Mint)
-		if err != nil {
-			return []domain.SwapResult{}, err
-		}
-		receivedTokenDetail, err := sr.GetTokenNameAndSymbol(context.TODO(), received[i].Mint)
-		if err != nil {
-			return []domain.SwapResult{}, err
-		}
+// `walletLamportDelta` calculates the SOL balance change of walletAddress within a txn
+func walletLamportDelta(payload domain.TransactionResult, walletAddress string) float64 {
+	return lamportDelta(payload, slices.Index(resolveAccountKeys(payload), walletAddress))
+}
 
-		swaps = append(swaps, domain.SwapResult{
-			SentAmount:      sent[i].UITokenAmount.UIAmount,
-			SentSymbol:      sentTokenDetail[1],
-			SentAddress:     sent[i].Mint,
-			ReceivedAddress: received[i].Mint,
-			ReceivedAmount:  received[i].UITokenAmount.UIAmount,
-			ReceivedSymbol:  receivedTokenDetail[1],
-		})
-	}
-	return swaps, nil
-}
-
-// `GetTokenNameAndSymbol` retrieves the name and symbol for a Solana token
-// by fetching and decoding its metadata account
-// returns a string slice [name, symbol]
-func (sr *solanaWebSocketRepo) GetTokenNameAndSymbol(ctx context.Context, tokenAddress string) ([]string, error) {
-	mint := solanago.MustPublicKeyFromBase58(tokenAddress)
-	rpcClient := solanarpc.New("https://api.mainnet-beta.solana.com")
-
-	// find where metadata is stored
-	// using token mint, and token programID
-	seeds := [][]byte{
-		[]byte("metadata"),
-		token_metadata.ProgramID.Bytes(),
-		mint.Bytes(),
-	}
-	/* Extraction */
-	mdAddr, _, err := solanago.FindProgramAddress(seeds, token_metadata.ProgramID)
-	if err != nil {
-		return []string{}, fmt.Errorf("unable to find metadata address: %w", err)
+// `lamportDelta` calculates the SOL balance change of the account at accountIndex
+// net of the txn fee when the account is the fee payer (index 0)
+func lamportDelta(payload domain.TransactionResult, accountIndex int) float64 {
+	meta := payload.Result.Meta
+	if accountIndex < 0 || accountIndex >= len(meta.PreBalances) || accountIndex >= len(meta.PostBalances) {
+		return 0
 	}
-	acc, err := rpcClient.GetAccountInfo(context.Background(), mdAddr)
-	if err != nil {
-		return []string{}, fmt.Errorf("unable to find account info: %w", err)
-	}
-
-	/* Transformation */
-	data := acc.Value.Data.GetBinary()
-	var metadata token_metadata.Metadata
-	decoder := bin.NewBorshDecoder(data)
-	if err := metadata.UnmarshalWithDecoder(decoder); err != nil {
-		return []string{}, fmt.Errorf("unable to deserialize data: %w", err)
+	delta := int64(meta.PostBalances[accountIndex]) - int64(meta.PreBalances[accountIndex])
+	if accountIndex == 0 {
+		delta += int64(meta.Fee)
 	}
+	return float64(delta) / float64(solanago.LAMPORTS_PER_SOL)
+}
 
-	r, _ := regexp.Compile(`\x00+`)
-	name := r.ReplaceAllString(metadata.Data.Name, "")
-	symbol := r.ReplaceAllString(metadata.Data.Symbol, "")
+// `resolveAccountKeys` returns the full account key list of a txn
+// static keys followed by writable then readonly lookup table addresses,
+// matching the indexing used by balances and instructions
+func resolveAccountKeys(payload domain.TransactionResult) []string {
+	loaded := payload.Result.Meta.LoadedAddresses
+	keys := make([]string, 0, len(payload.Result.Transaction.Message.AccountKeys)+len(loaded.Writable)+len(loaded.Readonly))
+	keys = append(keys, payload.Result.Transaction.Message.AccountKeys...)
+	keys = append(keys, loaded.Writable...)
+	keys = append(keys, loaded.Readonly...)
+	return keys
+}
 
-	return []string{name, symbol}, nil
+// `swapLegSymbol` resolves the symbol of a swap leg's mint
+// native SOL legs are labelled without an RPC call. Mints whose metadata cannot be resolved
+// are left without a symbol rather than failing the txn, their address still identifying them
+func (sr *solanaWebSocketRepo) swapLegSymbol(mint string) string {
+	if mint == domain.WrappedSOLMint {
+		return nativeSolSymbol
+	}
+	metadata, err := sr.metadata.Resolve(context.TODO(), mint)
+	if err != nil {
+		log.Printf("unable to resolve symbol of %s: %v", mint, err)
+		return ""
+	}
+	return metadata.Symbol
 }
 
 // `AccountListen` creates a channel for recieving account notifications
-// returns a read only channel that receives a HeliusLogResponse
-func (sr *solanaWebSocketRepo) AccountListen(ctx context.Context) (<-chan domain.HeliusLogResponse, error) {
-	updates := make(chan domain.HeliusLogResponse, 10)
+// returns a read only channel that receives a WalletNotification
+func (sr *solanaWebSocketRepo) AccountListen(ctx context.Context) (<-chan domain.WalletNotification, error) {
+	updates := make(chan domain.WalletNotification, 10)
 	sr.mu.Lock()
 	defer sr.mu.Unlock()
 	sr.subs = append(sr.subs, updates)
@@ -373,7 +712,7 @@ func (sr *solanaWebSocketRepo) AccountListen(ctx context.Context) (<-chan domain
 
 // `StopAccountListen` unsubscribes from account notifications
 // by removing specified channel from subscription list and closing it
-func (sr *solanaWebSocketRepo) StopAccountListen(ch <-chan domain.HeliusLogResponse) {
+func (sr *solanaWebSocketRepo) StopAccountListen(ch <-chan domain.WalletNotification) {
 	sr.mu.Lock()
 	defer sr.mu.Unlock()
 	for i, sub := range sr.subs {
@@ -385,28 +724,6 @@ func (sr *solanaWebSocketRepo) StopAccountListen(ch <-chan domain.HeliusLogRespo
 	}
 }
 
-/*
- ** Deprecated **
- */
-func (sr *solanaWebSocketRepo) AccountUnsubscribe(ctx context.Context, walletAddress string, userId int) (bool, error) {
-	sr.mu.Lock()
-	defer sr.mu.Unlock()
-
-	msg := domain.HeliusRequest{
-		JsonRPC: "2.0",
-		ID:      userId,
-		Method:  "accountUnsubscribe",
-	}
-	if err := sr.Websocket.WriteJSON(msg); err != nil {
-		return false, fmt.Errorf("failed to send msg to ws: %w", err)
-	}
-	var unsubscribeResponse domain.HeliusUnsubscribeResponse
-	if err := sr.Websocket.ReadJSON(&unsubscribeResponse); err != nil {
-		return false, fmt.Errorf("failed to read msg to ws: %w", err)
-	}
-	return unsubscribeResponse.Result, nil
-}
-
 /*
  ** Deprecated **
  */
@@ -415,7 +732,7 @@ func (sr *solanaWebSocketRepo) AccountSubscribe(ctx context.Context, walletAddre
 	defer sr.mu.Unlock()
 	msg := domain.HeliusRequest{
 		JsonRPC: "2.0",
-		ID:      userId,
+		ID:      sr.registry.nextRequestID(),
 		Method:  "accountSubscribe",
 		Params: []any{
 			walletAddress,
@@ -429,7 +746,7 @@ func (sr *solanaWebSocketRepo) AccountSubscribe(ctx context.Context, walletAddre
 	sr.pending.Store(msg.ID, responseCh)
 	defer sr.pending.Delete(msg.ID)
 
-	if err := sr.Websocket.WriteJSON(msg); err != nil {
+	if err := sr.ws.WriteJSON(msg); err != nil {
 		return fmt.Errorf("failed to send subscription request: %w", err)
 	}
 
@@ -438,7 +755,7 @@ func (sr *solanaWebSocketRepo) AccountSubscribe(ctx context.Context, walletAddre
 		if res.Error != nil {
 			return fmt.Errorf("subscription error: %v", res.Error.Message)
 		}
-		log.Printf("Subscribed to %s | ID: %d\n", walletAddress, res.Result)
+		log.Printf("Subscribed to %s | ID: %s\n", walletAddress, res.Result)
 		return nil
 	case <-time.After(30 * time.Second):
 		return fmt.Errorf("subscription timeout")
@@ -11,11 +11,12 @@ import (
 type Router struct {
 	tokenHandler   *handler.TokenHandler
 	accountHandler *handler.AccountHandler
+	walletHandler  *handler.WalletHandler
 }
 
 // `NewRouter` creates a new Router instance with its handlers being injected
-func NewRouter(th *handler.TokenHandler, ah *handler.AccountHandler) *Router {
-	return &Router{tokenHandler: th, accountHandler: ah}
+func NewRouter(th *handler.TokenHandler, ah *handler.AccountHandler, wh *handler.WalletHandler) *Router {
+	return &Router{tokenHandler: th, accountHandler: ah, walletHandler: wh}
 }
 
 // `LoadRoutes` initalizes and returns configured chi.Mux router
@@ -25,6 +26,7 @@ func (r *Router) LoadRoutes() *chi.Mux {
 	router.Use(middleware.Logger)
 	router.Route("/v0/token", r.tokenRoutes)
 	router.Route("/v0/track", r.accountRoutes)
+	router.Route("/v0/wallet", r.walletRoutes)
 
 	return router
 }
@@ -33,6 +35,8 @@ func (r *Router) LoadRoutes() *chi.Mux {
 func (r *Router) tokenRoutes(router chi.Router) {
 	// GET /v0/token/...
 	router.Get("/{token_address}", r.tokenHandler.GetTokenDetails)
+	// GET /v0/token/.../safety
+	router.Get("/{token_address}/safety", r.tokenHandler.GetTokenSafety)
 	// DELETE /v0/token/...
 	router.Delete("/{token_address}", r.tokenHandler.DeleteToken)
 }
@@ -41,8 +45,20 @@ func (r *Router) tokenRoutes(router chi.Router) {
 func (r *Router) accountRoutes(router chi.Router) {
 	// POST /v0/track/...
 	router.Post("/", r.accountHandler.CreateUserEntry)
+	// GET /v0/track/events?telegram_id=
+	router.Get("/events", r.accountHandler.StreamNotifications)
 	// POST /v0/track/...
 	router.Post("/{wallet_address}", r.accountHandler.TrackWallet)
 	// PUT /v0/track/...
 	router.Put("/{wallet_address}", r.accountHandler.UntrackWallet)
 }
+
+// `walletRoutes` defines routes for wallet activity under /v0/wallet path
+func (r *Router) walletRoutes(router chi.Router) {
+	// GET /v0/wallet/...
+	router.Get("/{wallet_address}", r.walletHandler.GetWalletPortfolio)
+	// GET /v0/wallet/.../trades
+	router.Get("/{wallet_address}/trades", r.walletHandler.GetWalletTrades)
+	// GET /v0/wallet/.../pnl
+	router.Get("/{wallet_address}/pnl", r.walletHandler.GetWalletPnL)
+}
@@ -5,20 +5,69 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"slices"
+	"sync"
+	"time"
 
+	"github.com/jakobsym/aura/internal/domain"
 	"github.com/jakobsym/aura/internal/repository"
 )
 
+// rate at which active wallets are resubscribed on startup
+const rehydrateInterval = 200 * time.Millisecond
+
+// number of routed notifications buffered per listener before they are dropped
+const listenerBuffer = 100
+
 // `AccountService` provides wallet tracking business logic by receiving data
-// from the SolanaWebSocketRepo, and Postgres AccountRepo
+// from the SolanaWebSocketRepo, and Postgres AccountRepo.
+// Trades are recorded through the TradeRepo, backfill progress through the BackfillRepo.
+// Backfilled trades are valued at historical SOL prices from the SOLPriceHistory
 type AccountService struct {
-	solanaRepo repository.SolanaWebSocketRepo
-	psqlRepo   repository.AccountRepo
+	solanaRepo   repository.SolanaWebSocketRepo
+	psqlRepo     repository.AccountRepo
+	tradeRepo    repository.TradeRepo
+	tokenRepo    repository.SolanaTokenRepo
+	backfillRepo repository.BackfillRepo
+	solHistory   repository.SOLPriceHistory
+
+	listenersMu sync.Mutex
+	listeners   []chan domain.WalletNotification // delivery layer consumers of routed notifications
 }
 
 // `NewAccountService` creates and returns a new AccountService with required dependencies
-func NewAccountService(sr repository.SolanaWebSocketRepo, pr repository.AccountRepo) *AccountService {
-	return &AccountService{solanaRepo: sr, psqlRepo: pr}
+func NewAccountService(sr repository.SolanaWebSocketRepo, pr repository.AccountRepo, tr repository.TradeRepo, tkr repository.SolanaTokenRepo, br repository.BackfillRepo, sph repository.SOLPriceHistory) *AccountService {
+	return &AccountService{
+		solanaRepo:   sr,
+		psqlRepo:     pr,
+		tradeRepo:    tr,
+		tokenRepo:    tkr,
+		backfillRepo: br,
+		solHistory:   sph,
+	}
+}
+
+// `ListenNotifications` registers a listener for wallet events addressed to their recipients
+// consumed by the delivery layer (i.e: Telegram interface) through the notification stream
+func (as *AccountService) ListenNotifications() <-chan domain.WalletNotification {
+	ch := make(chan domain.WalletNotification, listenerBuffer)
+	as.listenersMu.Lock()
+	defer as.listenersMu.Unlock()
+	as.listeners = append(as.listeners, ch)
+	return ch
+}
+
+// `StopListenNotifications` removes a listener registered by ListenNotifications and closes it
+func (as *AccountService) StopListenNotifications(ch <-chan domain.WalletNotification) {
+	as.listenersMu.Lock()
+	defer as.listenersMu.Unlock()
+	for i, listener := range as.listeners {
+		if listener == ch {
+			as.listeners = slices.Delete(as.listeners, i, i+1)
+			close(listener)
+			return
+		}
+	}
 }
 
 // `MonitorAccountSubscription` initiates and manages wallet monitoring subscription(s).
@@ -33,14 +82,90 @@ func (as *AccountService) MonitorAccountSubsription(ctx context.Context) error {
 	go func() {
 		defer as.solanaRepo.StopAccountListen(updates)
 		for update := range updates {
-			log.Printf("transaction detected: %+v", update)
+			as.persistTrade(ctx, update)
+			as.routeNotification(update)
 		}
 	}()
+	go as.rehydrateSubscriptions(ctx)
+	go as.resumeBackfills(ctx)
 	return nil
 }
 
+// `persistTrade` stores the trade carried by a swap notification
+func (as *AccountService) persistTrade(ctx context.Context, notification domain.WalletNotification) {
+	if notification.Event.Type != domain.TxnSwap || notification.Event.Swap == nil {
+		return
+	}
+	trade := newTrade(notification.WalletAddress, notification.Signature, notification.Slot, notification.BlockTime, *notification.Event.Swap)
+	if err := recordTrade(ctx, as.tradeRepo, as.tokenRepo, trade); err != nil {
+		log.Printf("failed to persist trade %s: %v", notification.Signature, err)
+	}
+}
+
+// `routeNotification` addresses a wallet event to the users subscribed to the wallet
+// and pushes it to every listener. Events for wallets with no subscribers are dropped.
+func (as *AccountService) routeNotification(notification domain.WalletNotification) {
+	recipients, err := as.psqlRepo.GetWalletSubscribers(notification.WalletAddress)
+	if err != nil {
+		log.Printf("failed to fetch subscribers for %s: %v", notification.WalletAddress, err)
+		return
+	}
+	if len(recipients) == 0 {
+		return
+	}
+	notification.Recipients = recipients
+	log.Printf("transaction detected: %+v", notification)
+
+	as.listenersMu.Lock()
+	defer as.listenersMu.Unlock()
+	if len(as.listeners) == 0 {
+		log.Printf("no notification listener, dropping %s", notification.Signature)
+		return
+	}
+	for _, listener := range as.listeners {
+		select {
+		case listener <- notification:
+		default:
+			log.Println("Notification listener full, dropping notification")
+		}
+	}
+}
+
+// `rehydrateSubscriptions` loads every active wallet from the database
+// and resubscribes to its logs, pacing requests by rehydrateInterval.
+// Each wallet's users are re-read right before subscribing, as they may untrack it meanwhile
+func (as *AccountService) rehydrateSubscriptions(ctx context.Context) {
+	subscriptions, err := as.psqlRepo.GetActiveSubscriptions()
+	if err != nil {
+		log.Printf("failed to load active subscriptions: %v", err)
+		return
+	}
+	log.Printf("rehydrating %d active wallet(s)", len(subscriptions))
+
+	ticker := time.NewTicker(rehydrateInterval)
+	defer ticker.Stop()
+	for _, sub := range subscriptions {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+		userIds, err := as.psqlRepo.GetSubscribers(sub.WalletAddress)
+		if err != nil {
+			log.Printf("failed to rehydrate %s: %v", sub.WalletAddress, err)
+			continue
+		}
+		for _, userId := range userIds {
+			if err := as.solanaRepo.LogsSubscribe(ctx, sub.WalletAddress, userId); err != nil {
+				log.Printf("failed to rehydrate %s for userID %d: %v", sub.WalletAddress, userId, err)
+			}
+		}
+	}
+}
+
 // `TrackWallet` starts tracking a wallet for a specific telegram user.
 // Creates necessary database records, and subscribes to Solana log events for updates.
+// Wallets new to the system have their recent trade history backfilled in the background.
 func (as *AccountService) TrackWallet(walletAddress string, telegramId int) error {
 	userId, err := as.psqlRepo.GetUserID(telegramId)
 	if err != nil {
@@ -62,28 +187,29 @@ func (as *AccountService) TrackWallet(walletAddress string, telegramId int) erro
 		if err != nil {
 			return err
 		}
-		if err := as.psqlRepo.CreateSubscription(walletAddress, userId, walletId); err != nil {
-			return err
-		}
 	}
-
-	return as.solanaRepo.LogsSubscribe(context.TODO(), walletAddress, userId)
+	if err := as.psqlRepo.CreateSubscription(walletAddress, userId, walletId); err != nil {
+		return err
+	}
+	if err := as.solanaRepo.LogsSubscribe(context.TODO(), walletAddress, userId); err != nil {
+		return err
+	}
+	as.startBackfill(walletAddress)
+	return nil
 }
 
 // `UntrackWallet` stops tracking a wallet for a given telegram user.
-// removes subscription and cleans up resources
-func (as *AccountService) UntrackWallet(walletAddress string, userId int) error {
-	isTracked, err := as.psqlRepo.RemoveSubscription(walletAddress, userId)
+// removes subscription and, once no user tracks the wallet, unsubscribes from its logs
+func (as *AccountService) UntrackWallet(walletAddress string, telegramId int) error {
+	userId, err := as.psqlRepo.GetUserID(telegramId)
 	if err != nil {
 		return err
 	}
-	if !isTracked {
-		_, err := as.solanaRepo.AccountUnsubscribe(context.TODO(), walletAddress, userId)
-		if err != nil {
-			return err
-		}
+	stillTracked, err := as.psqlRepo.RemoveSubscription(walletAddress, userId)
+	if err != nil {
+		return err
 	}
-	return nil
+	return as.solanaRepo.LogsUnsubscribe(context.TODO(), walletAddress, userId, stillTracked)
 }
 
 // `CreateUser` creates a new user record in the database
@@ -0,0 +1,97 @@
+// Package `service` calls repository methods to implement business logic
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+const (
+	// how long a token request waits on its age lookup, the walk itself carries on in the background
+	tokenAgeWait = 10 * time.Second
+	// how long after a failed age lookup the token's history is walked again
+	tokenAgeRetryTTL = 10 * time.Minute
+)
+
+// `ageLookup` is a walk of a token's signature history back to its creation
+// done is closed once createdAt or err is set
+type ageLookup struct {
+	done       chan struct{}
+	createdAt  time.Time
+	err        error
+	finishedAt time.Time
+}
+
+// `tokenAge` returns when a token was created, waiting up to wait on its age lookup
+// Returns false while the lookup is still running, or has failed. Lookups run to the end of
+// the token's history detached from ctx, and persist the age themselves once found.
+func (ts *TokenService) tokenAge(ctx context.Context, tokenAddress string, wait time.Duration) (time.Time, bool) {
+	lookup := ts.startAgeLookup(tokenAddress)
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-lookup.done:
+	case <-timer.C:
+		return time.Time{}, false
+	case <-ctx.Done():
+		return time.Time{}, false
+	}
+	if lookup.err != nil {
+		return time.Time{}, false
+	}
+	// the caller stores the age with the rest of the token
+	ts.forgetAgeLookup(tokenAddress, lookup)
+	return lookup.createdAt, true
+}
+
+// `startAgeLookup` returns the age lookup of a token, starting one unless
+// it is already running, found the age, or failed less than tokenAgeRetryTTL ago
+func (ts *TokenService) startAgeLookup(tokenAddress string) *ageLookup {
+	ts.ageMu.Lock()
+	defer ts.ageMu.Unlock()
+	if lookup, ok := ts.ageLookups[tokenAddress]; ok {
+		select {
+		case <-lookup.done:
+			if lookup.err == nil || time.Since(lookup.finishedAt) < tokenAgeRetryTTL {
+				return lookup
+			}
+		default:
+			return lookup
+		}
+	}
+	lookup := &ageLookup{done: make(chan struct{})}
+	ts.ageLookups[tokenAddress] = lookup
+
+	go func() {
+		createdAt, err := ts.solanaRepo.GetTokenAge(context.Background(), tokenAddress)
+		if err != nil {
+			log.Printf("unable to determine age of %s, retrying in %s: %v", tokenAddress, tokenAgeRetryTTL, err)
+		}
+		ts.ageMu.Lock()
+		lookup.createdAt, lookup.err, lookup.finishedAt = createdAt, err, time.Now()
+		ts.ageMu.Unlock()
+		close(lookup.done)
+		if err != nil {
+			return
+		}
+		// tokens not stored yet keep the lookup, for the request storing them to pick up
+		stored, err := ts.psqlRepo.UpdateTokenAge(tokenAddress, createdAt)
+		if err != nil {
+			log.Printf("unable to store age of %s: %v", tokenAddress, err)
+		}
+		if stored {
+			ts.forgetAgeLookup(tokenAddress, lookup)
+		}
+	}()
+	return lookup
+}
+
+// `forgetAgeLookup` drops a finished lookup once its age is stored
+func (ts *TokenService) forgetAgeLookup(tokenAddress string, lookup *ageLookup) {
+	ts.ageMu.Lock()
+	defer ts.ageMu.Unlock()
+	if ts.ageLookups[tokenAddress] == lookup {
+		delete(ts.ageLookups, tokenAddress)
+	}
+}
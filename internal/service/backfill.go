@@ -0,0 +1,141 @@
+// Package `service` calls repository methods to implement business logic
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/jakobsym/aura/internal/domain"
+)
+
+// backfill depth defaults, overridden by BACKFILL_MAX_TXNS and BACKFILL_MAX_DAYS
+const (
+	defaultBackfillMaxTxns = 500
+	defaultBackfillMaxDays = 30
+)
+
+const (
+	backfillPageSize = 100                    // signatures requested per page
+	backfillInterval = 100 * time.Millisecond // rate at which historical txns are fetched
+)
+
+// `startBackfill` creates a backfill job for a wallet new to the system
+// and runs it in the background. Wallets that already have a job are left as is.
+func (as *AccountService) startBackfill(walletAddress string) {
+	job := domain.BackfillJob{
+		WalletAddress: walletAddress,
+		Status:        domain.BackfillRunning,
+		MaxTxns:       envInt("BACKFILL_MAX_TXNS", defaultBackfillMaxTxns),
+		Cutoff:        time.Now().UTC().AddDate(0, 0, -envInt("BACKFILL_MAX_DAYS", defaultBackfillMaxDays)),
+	}
+	created, err := as.backfillRepo.CreateBackfillJob(job)
+	if err != nil {
+		log.Printf("failed to create backfill job for %s: %v", walletAddress, err)
+		return
+	}
+	if created {
+		go as.runBackfill(context.Background(), job)
+	}
+}
+
+// `resumeBackfills` continues every backfill job left unfinished by a previous run
+func (as *AccountService) resumeBackfills(ctx context.Context) {
+	jobs, err := as.backfillRepo.GetRunningBackfillJobs()
+	if err != nil {
+		log.Printf("failed to load backfill jobs: %v", err)
+		return
+	}
+	for _, job := range jobs {
+		log.Printf("resuming backfill for %s after %d txn(s)", job.WalletAddress, job.Processed)
+		as.runBackfill(ctx, job)
+	}
+}
+
+// `runBackfill` pages backwards through a wallet's signatures recording the trades found
+// progress is persisted after every page so an interrupted job resumes where it stopped.
+// Failed requests leave the job running, to be retried on the next startup.
+func (as *AccountService) runBackfill(ctx context.Context, job domain.BackfillJob) {
+	ticker := time.NewTicker(backfillInterval)
+	defer ticker.Stop()
+
+	for job.Status == domain.BackfillRunning {
+		err := as.backfillPage(ctx, ticker, &job)
+		as.saveBackfill(job)
+		if err != nil {
+			log.Printf("backfill for %s stopped after %d txn(s): %v", job.WalletAddress, job.Processed, err)
+			return
+		}
+	}
+	log.Printf("backfill for %s finished after %d txn(s)", job.WalletAddress, job.Processed)
+}
+
+// `backfillPage` processes the next page of signatures older than the job's cursor
+// advancing the cursor past every txn handled, and marking the job done once its depth is reached.
+// Txns that fail are logged, recorded in the job, and skipped. Only a cancelled ctx,
+// or a failure to page signatures, stops the job.
+func (as *AccountService) backfillPage(ctx context.Context, ticker *time.Ticker, job *domain.BackfillJob) error {
+	limit := min(backfillPageSize, job.MaxTxns-job.Processed)
+	if limit <= 0 {
+		job.Status = domain.BackfillDone
+		return nil
+	}
+	signatures, err := as.solanaRepo.GetSignaturesForAddress(job.WalletAddress, job.Before, limit)
+	if err != nil {
+		return fmt.Errorf("failed to fetch signatures: %w", err)
+	}
+	if len(signatures) == 0 {
+		job.Status = domain.BackfillDone
+		return nil
+	}
+
+	for _, sig := range signatures {
+		if time.Unix(sig.BlockTime, 0).Before(job.Cutoff) {
+			job.Status = domain.BackfillDone
+			return nil
+		}
+		// failed txns moved no funds
+		if sig.Err == nil {
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			if err := as.backfillTxn(ctx, job.WalletAddress, sig); err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				log.Printf("backfill for %s skipping %s: %v", job.WalletAddress, sig.Signature, err)
+				job.Failed = append(job.Failed, sig.Signature)
+			}
+		}
+		job.Before = sig.Signature
+		job.Processed++
+	}
+	return nil
+}
+
+// `backfillTxn` fetches and classifies a historical txn, recording it when it is a swap
+func (as *AccountService) backfillTxn(ctx context.Context, walletAddress string, sig domain.SignatureInfo) error {
+	payload, err := as.solanaRepo.GetTxnData(sig.Signature)
+	if err != nil {
+		return err
+	}
+	event, err := as.solanaRepo.ClassifyTxn(payload, walletAddress)
+	if err != nil {
+		return err
+	}
+	if event.Type != domain.TxnSwap || event.Swap == nil {
+		return nil
+	}
+	trade := newTrade(walletAddress, sig.Signature, sig.Slot, time.Unix(sig.BlockTime, 0).UTC(), *event.Swap)
+	return recordHistoricalTrade(ctx, as.tradeRepo, as.solHistory, trade)
+}
+
+// `saveBackfill` persists a backfill job's progress
+func (as *AccountService) saveBackfill(job domain.BackfillJob) {
+	if err := as.backfillRepo.UpdateBackfillJob(job); err != nil {
+		log.Printf("failed to save backfill progress for %s: %v", job.WalletAddress, err)
+	}
+}
@@ -0,0 +1,25 @@
+// Package `service` calls repository methods to implement business logic
+package service
+
+import (
+	"os"
+	"strconv"
+)
+
+// `envInt` reads a positive integer from the environment, falling back to def
+func envInt(key string, def int) int {
+	n, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || n <= 0 {
+		return def
+	}
+	return n
+}
+
+// `envFloat` reads a non-negative float from the environment, falling back to def
+func envFloat(key string, def float64) float64 {
+	f, err := strconv.ParseFloat(os.Getenv(key), 64)
+	if err != nil || f < 0 {
+		return def
+	}
+	return f
+}
@@ -0,0 +1,102 @@
+// Package `service` calls repository methods to implement business logic
+package service
+
+import (
+	"github.com/jakobsym/aura/internal/domain"
+)
+
+// quantities at or below this are treated as fully closed
+const positionDustTolerance = 1e-9
+
+// `lot` represents a quantity of a token acquired at a USD cost per token
+type lot struct {
+	quantity float64
+	unitCost float64
+}
+
+// `position` accumulates a wallet's open lots and realized PnL in a single token
+// FIFO keeps every lot, oldest first. Average cost pools all lots into one.
+type position struct {
+	mint     string
+	method   string
+	lots     []lot
+	realized float64
+}
+
+// `buy` opens a lot of quantity tokens acquired for cost USD
+func (p *position) buy(quantity, cost float64) {
+	if quantity <= 0 {
+		return
+	}
+	if p.method == domain.PnLAverage && len(p.lots) > 0 {
+		pooled := &p.lots[0]
+		total := pooled.quantity*pooled.unitCost + cost
+		pooled.quantity += quantity
+		pooled.unitCost = total / pooled.quantity
+		return
+	}
+	p.lots = append(p.lots, lot{quantity: quantity, unitCost: cost / quantity})
+}
+
+// `sell` closes quantity tokens disposed of for proceeds USD, oldest lots first
+// quantity beyond the open lots (acquired before the trade history) has no known cost basis,
+// so its share of the proceeds is not realized
+func (p *position) sell(quantity, proceeds float64) {
+	if quantity <= 0 {
+		return
+	}
+	unitPrice := proceeds / quantity
+	for quantity > positionDustTolerance && len(p.lots) > 0 {
+		open := &p.lots[0]
+		matched := min(quantity, open.quantity)
+		p.realized += matched * (unitPrice - open.unitCost)
+		open.quantity -= matched
+		quantity -= matched
+		if open.quantity <= positionDustTolerance {
+			p.lots = p.lots[1:]
+		}
+	}
+}
+
+// `summary` converts a position into a domain.TokenPosition, without a price
+func (p *position) summary() domain.TokenPosition {
+	tp := domain.TokenPosition{Mint: p.mint, RealizedPnL: p.realized}
+	for _, open := range p.lots {
+		tp.Quantity += open.quantity
+		tp.CostBasis += open.quantity * open.unitCost
+	}
+	if tp.Quantity > positionDustTolerance {
+		tp.AvgEntry = tp.CostBasis / tp.Quantity
+	}
+	return tp
+}
+
+// `buildPositions` replays a wallet's trades, oldest first, into per token positions
+// quote mints (stablecoins and SOL) are what trades are denominated in and are not tracked.
+// Trades without a USD value cannot contribute to a cost basis and are skipped.
+func buildPositions(trades []domain.Trade, method string) []*position {
+	var positions []*position
+	byMint := make(map[string]*position)
+	positionFor := func(mint string) *position {
+		p, ok := byMint[mint]
+		if !ok {
+			p = &position{mint: mint, method: method}
+			byMint[mint] = p
+			positions = append(positions, p)
+		}
+		return p
+	}
+
+	for _, trade := range trades {
+		if !trade.Priced || trade.USDValue <= 0 {
+			continue
+		}
+		if !quoteMints[trade.InputMint] {
+			positionFor(trade.InputMint).sell(trade.InputAmount, trade.USDValue)
+		}
+		if !quoteMints[trade.OutputMint] {
+			positionFor(trade.OutputMint).buy(trade.OutputAmount, trade.USDValue)
+		}
+	}
+	return positions
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"math"
+	"testing"
+
+	"github.com/jakobsym/aura/internal/domain"
+)
+
+const testMint = "TestMint1111111111111111111111111111111111"
+
+// `buyTrade` spends usd USDC on quantity testMint
+func buyTrade(quantity, usd float64) domain.Trade {
+	return domain.Trade{
+		InputMint:    domain.USDCMint,
+		InputAmount:  usd,
+		OutputMint:   testMint,
+		OutputAmount: quantity,
+		USDValue:     usd,
+		Priced:       true,
+	}
+}
+
+// `sellTrade` sells quantity testMint for usd USDC
+func sellTrade(quantity, usd float64) domain.Trade {
+	return domain.Trade{
+		InputMint:    testMint,
+		InputAmount:  quantity,
+		OutputMint:   domain.USDCMint,
+		OutputAmount: usd,
+		USDValue:     usd,
+		Priced:       true,
+	}
+}
+
+func TestBuildPositions(t *testing.T) {
+	unpriced := buyTrade(50, 0)
+	unpriced.Priced = false
+
+	tests := []struct {
+		name      string
+		method    string
+		trades    []domain.Trade
+		realized  float64
+		quantity  float64
+		costBasis float64
+	}{
+		{
+			name:      "partial sell keeps remaining cost basis",
+			method:    domain.PnLFIFO,
+			trades:    []domain.Trade{buyTrade(100, 100), sellTrade(40, 80)},
+			realized:  40,
+			quantity:  60,
+			costBasis: 60,
+		},
+		{
+			name:      "partial sell across lots fifo",
+			method:    domain.PnLFIFO,
+			trades:    []domain.Trade{buyTrade(10, 10), buyTrade(10, 20), sellTrade(15, 45)},
+			realized:  10*(3-1) + 5*(3-2),
+			quantity:  5,
+			costBasis: 10,
+		},
+		{
+			name:      "selling more than held realizes only the known lots",
+			method:    domain.PnLFIFO,
+			trades:    []domain.Trade{buyTrade(10, 10), sellTrade(15, 30)},
+			realized:  10,
+			quantity:  0,
+			costBasis: 0,
+		},
+		{
+			name:      "selling more than held average",
+			method:    domain.PnLAverage,
+			trades:    []domain.Trade{buyTrade(10, 10), buyTrade(10, 30), sellTrade(25, 100)},
+			realized:  20 * (4 - 2),
+			quantity:  0,
+			costBasis: 0,
+		},
+		{
+			name:      "fifo realizes against the oldest lot",
+			method:    domain.PnLFIFO,
+			trades:    []domain.Trade{buyTrade(10, 10), buyTrade(10, 30), sellTrade(10, 40)},
+			realized:  30,
+			quantity:  10,
+			costBasis: 30,
+		},
+		{
+			name:      "average realizes against the pooled cost",
+			method:    domain.PnLAverage,
+			trades:    []domain.Trade{buyTrade(10, 10), buyTrade(10, 30), sellTrade(10, 40)},
+			realized:  20,
+			quantity:  10,
+			costBasis: 20,
+		},
+		{
+			name:      "unpriced trades are skipped",
+			method:    domain.PnLFIFO,
+			trades:    []domain.Trade{buyTrade(10, 10), unpriced, sellTrade(10, 20)},
+			realized:  10,
+			quantity:  0,
+			costBasis: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			positions := buildPositions(tt.trades, tt.method)
+			if len(positions) != 1 {
+				t.Fatalf("got %d positions, want 1", len(positions))
+			}
+			got := positions[0].summary()
+			if !almostEqual(got.RealizedPnL, tt.realized) {
+				t.Errorf("realized = %v, want %v", got.RealizedPnL, tt.realized)
+			}
+			if !almostEqual(got.Quantity, tt.quantity) {
+				t.Errorf("quantity = %v, want %v", got.Quantity, tt.quantity)
+			}
+			if !almostEqual(got.CostBasis, tt.costBasis) {
+				t.Errorf("cost basis = %v, want %v", got.CostBasis, tt.costBasis)
+			}
+		})
+	}
+}
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-6
+}
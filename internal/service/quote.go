@@ -0,0 +1,94 @@
+// Package `service` calls repository methods to implement business logic
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jakobsym/aura/internal/domain"
+	"github.com/jakobsym/aura/internal/repository"
+)
+
+// how long a quote currency's USD rate is reused before being refetched
+const quoteRateTTL = 30 * time.Second
+
+// `quoteRate` is a cached USD value of one unit of a quote currency
+type quoteRate struct {
+	rate     float64
+	quotedAt time.Time
+}
+
+// `QuoteConverter` converts USD denominated prices and values into other quote currencies
+// SOL and USDC are priced through SolanaTokenRepo, fiat currencies through an FXProvider
+type QuoteConverter struct {
+	tokenRepo repository.SolanaTokenRepo
+	fx        repository.FXProvider
+
+	mu    sync.Mutex
+	rates map[string]quoteRate
+}
+
+// `NewQuoteConverter` creates and returns a new QuoteConverter with required dependencies
+func NewQuoteConverter(tkr repository.SolanaTokenRepo, fx repository.FXProvider) *QuoteConverter {
+	return &QuoteConverter{tokenRepo: tkr, fx: fx, rates: make(map[string]quoteRate)}
+}
+
+// `ParseQuote` normalizes a requested quote currency, defaulting to USD
+// returns domain.ErrInvalidQuote for unsupported currencies
+func ParseQuote(quote string) (string, error) {
+	quote = strings.ToUpper(quote)
+	switch quote {
+	case "":
+		return domain.QuoteUSD, nil
+	case domain.QuoteUSD, domain.QuoteSOL, domain.QuoteUSDC, domain.QuoteEUR:
+		return quote, nil
+	}
+	return "", fmt.Errorf("%w: %s", domain.ErrInvalidQuote, quote)
+}
+
+// `Rate` retrieves the USD value of one unit of quote, reused for quoteRateTTL
+// USD values are converted into quote by dividing by the rate
+func (qc *QuoteConverter) Rate(ctx context.Context, quote string) (float64, error) {
+	if quote == domain.QuoteUSD {
+		return 1, nil
+	}
+	qc.mu.Lock()
+	cached, ok := qc.rates[quote]
+	qc.mu.Unlock()
+	if ok && time.Since(cached.quotedAt) < quoteRateTTL {
+		return cached.rate, nil
+	}
+
+	var (
+		rate float64
+		err  error
+	)
+	switch quote {
+	case domain.QuoteSOL, domain.QuoteUSDC:
+		mint := domain.WrappedSOLMint
+		if quote == domain.QuoteUSDC {
+			mint = domain.USDCMint
+		}
+		var price domain.TokenPrice
+		price, err = qc.tokenRepo.GetTokenPrice(ctx, mint)
+		rate = price.Price
+	case domain.QuoteEUR:
+		rate, err = qc.fx.GetRate(ctx, quote)
+	default:
+		return 0, fmt.Errorf("%w: %s", domain.ErrInvalidQuote, quote)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch %s rate: %w", quote, err)
+	}
+	if rate <= 0 {
+		return 0, fmt.Errorf("%w: %s", domain.ErrPriceNotFound, quote)
+	}
+
+	qc.mu.Lock()
+	qc.rates[quote] = quoteRate{rate: rate, quotedAt: time.Now()}
+	qc.mu.Unlock()
+	return rate, nil
+}
@@ -0,0 +1,193 @@
+// Package `service` calls repository methods to implement business logic
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jakobsym/aura/internal/domain"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// share of supply held by the top 10 holders above which concentration is flagged
+	holderConcentrationThreshold = 0.30
+	// share of LP burned or locked below which liquidity is flagged as withdrawable
+	lpSecuredThreshold = 0.95
+)
+
+// `GetTokenSafety` builds a rug check report for a token
+// covering mint / freeze authorities, metadata mutability, top 10 holder concentration,
+// and whether the LP of its main pool is burned or locked.
+// The LP status of venues without an LP token (i.e: Orca Whirlpool) is reported as unknown rather than flagged.
+func (ts *TokenService) GetTokenSafety(ctx context.Context, tokenAddress string) (*domain.TokenSafety, error) {
+	var (
+		mint     domain.MintInfo
+		metadata domain.TokenMetadata
+		holders  []domain.TokenHolder
+		supply   float64
+		pool     *domain.LiquidityPool
+	)
+	g, gctx := errgroup.WithContext(ctx)
+	g.Go(func() (err error) {
+		if mint, err = ts.solanaRepo.GetMintInfo(gctx, tokenAddress); err != nil {
+			return fmt.Errorf("failed to fetch mint: %w", err)
+		}
+		return nil
+	})
+	g.Go(func() (err error) {
+		// tokens without metadata have nothing left to mutate,
+		// any other failure leaves mutability unknown and fails the report
+		metadata, err = ts.solanaRepo.GetTokenMetadata(gctx, tokenAddress)
+		if err != nil && !errors.Is(err, domain.ErrTokenNotFound) {
+			return fmt.Errorf("failed to fetch metadata: %w", err)
+		}
+		return nil
+	})
+	g.Go(func() (err error) {
+		if holders, err = ts.solanaRepo.GetLargestHolders(gctx, tokenAddress); err != nil {
+			return fmt.Errorf("failed to fetch holders: %w", err)
+		}
+		return nil
+	})
+	g.Go(func() (err error) {
+		if supply, err = ts.solanaRepo.GetTokenSupply(gctx, tokenAddress); err != nil {
+			return fmt.Errorf("failed to fetch supply: %w", err)
+		}
+		return nil
+	})
+	g.Go(func() error {
+		p, err := ts.solanaRepo.GetLiquidityPool(gctx, tokenAddress)
+		switch {
+		case err == nil:
+			pool = &p
+		case !errors.Is(err, domain.ErrPoolNotFound):
+			return fmt.Errorf("failed to fetch pool: %w", err)
+		}
+		return nil
+	})
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	report := &domain.TokenSafety{
+		Address:                tokenAddress,
+		MintAuthorityRevoked:   mint.MintAuthority == "",
+		MintAuthority:          mint.MintAuthority,
+		FreezeAuthorityRevoked: mint.FreezeAuthority == "",
+		FreezeAuthority:        mint.FreezeAuthority,
+		MetadataMutable:        metadata.Mutable,
+		UpdateAuthority:        metadata.UpdateAuthority,
+		Risks:                  []string{},
+	}
+
+	// pool vaults hold liquidity rather than a holder's position
+	var vaults map[string]bool
+	if pool != nil {
+		vaults = map[string]bool{pool.BaseVault: true, pool.QuoteVault: true}
+	}
+	report.Top10HolderPct = topHolderShare(holders, supply, 10, vaults)
+
+	if pool != nil {
+		safety, err := ts.poolSafety(ctx, *pool)
+		if err != nil {
+			return nil, err
+		}
+		report.Pool = &safety
+	}
+
+	report.Risks = tokenRisks(report, mint.Extensions)
+	return report, nil
+}
+
+// `poolSafety` measures the share of a pool's LP that is burned, or locked
+// LP is considered locked while held by program derived addresses (i.e: lockers, escrows).
+// Pools without an LP token keep an unknown LP status.
+func (ts *TokenService) poolSafety(ctx context.Context, pool domain.LiquidityPool) (domain.PoolSafety, error) {
+	safety := domain.PoolSafety{
+		Address:      pool.Address,
+		Venue:        pool.Venue,
+		LPMint:       pool.LPMint,
+		LiquidityUSD: pool.LiquidityUSD,
+		LPStatus:     domain.LPStatusUnknown,
+	}
+	if pool.LPMint == "" {
+		return safety, nil
+	}
+	if pool.LPReserve > 0 {
+		safety.LPBurnedPct = min(max((pool.LPReserve-pool.LPSupply)/pool.LPReserve, 0), 1)
+	}
+	if pool.LPSupply <= 0 {
+		safety.LPStatus = lpStatus(safety)
+		return safety, nil
+	}
+
+	lpHolders, err := ts.solanaRepo.GetLargestHolders(ctx, pool.LPMint)
+	if err != nil {
+		return domain.PoolSafety{}, fmt.Errorf("failed to fetch lp holders: %w", err)
+	}
+	var locked float64
+	for _, holder := range lpHolders {
+		if holder.ProgramOwned {
+			locked += holder.Amount
+		}
+	}
+	// locked is a share of circulating LP, scaled into a share of all LP minted
+	safety.LPLockedPct = min(locked/pool.LPSupply, 1) * (1 - safety.LPBurnedPct)
+	safety.LPStatus = lpStatus(safety)
+	return safety, nil
+}
+
+// `lpStatus` reports LP as secured once at least lpSecuredThreshold of it is burned or locked
+func lpStatus(safety domain.PoolSafety) string {
+	if safety.LPBurnedPct+safety.LPLockedPct < lpSecuredThreshold {
+		return domain.LPStatusWithdrawable
+	}
+	return domain.LPStatusSecured
+}
+
+// `topHolderShare` returns the share of supply held by the n largest holders
+// skipping the accounts in exclude
+func topHolderShare(holders []domain.TokenHolder, supply float64, n int, exclude map[string]bool) float64 {
+	if supply <= 0 {
+		return 0
+	}
+	var held float64
+	for _, holder := range holders {
+		if n == 0 {
+			break
+		}
+		if exclude[holder.Account] {
+			continue
+		}
+		held += holder.Amount
+		n--
+	}
+	return min(held/supply, 1)
+}
+
+// `tokenRisks` lists the risk flags raised by a safety report and the mint's Token-2022 extensions
+func tokenRisks(report *domain.TokenSafety, extensions *domain.TokenExtensions) []string {
+	risks := []string{}
+	flag := func(raised bool, risk string) {
+		if raised {
+			risks = append(risks, risk)
+		}
+	}
+	flag(!report.MintAuthorityRevoked, domain.RiskMintAuthority)
+	flag(!report.FreezeAuthorityRevoked, domain.RiskFreezeAuthority)
+	flag(report.MetadataMutable, domain.RiskMutableMetadata)
+	flag(report.Top10HolderPct > holderConcentrationThreshold, domain.RiskHolderConcentration)
+	flag(report.Pool == nil, domain.RiskNoPool)
+	flag(report.Pool != nil && report.Pool.LPStatus == domain.LPStatusWithdrawable, domain.RiskLPUnlocked)
+	if extensions != nil {
+		flag(extensions.PermanentDelegate != "", domain.RiskPermanentDelegate)
+		flag(extensions.TransferFee != nil && extensions.TransferFee.BasisPoints > 0, domain.RiskTransferFee)
+		flag(extensions.TransferHookProgram != "", domain.RiskTransferHook)
+		flag(extensions.NonTransferable, domain.RiskNonTransferable)
+		flag(extensions.DefaultAccountFrozen, domain.RiskDefaultFrozen)
+		flag(extensions.Paused, domain.RiskPaused)
+	}
+	return risks
+}
@@ -0,0 +1,71 @@
+// Package `service` calls repository methods to implement business logic
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/jakobsym/aura/internal/domain"
+)
+
+// `burnAddresses` holds owners whose balances are burned for every token
+var burnAddresses = []string{
+	"1nc1nerator11111111111111111111111111111111", // Solana incinerator
+}
+
+// `SupplyExclusions` maps a token mint to the addresses excluded from its circulating supply
+// i.e: locked or vesting accounts, either token accounts or their owners
+type SupplyExclusions map[string][]string
+
+// `LoadSupplyExclusions` reads SupplyExclusions from a JSON file at path
+// i.e: {"<mint>": ["<token account or owner>", ...]}. An empty path loads no exclusions.
+func LoadSupplyExclusions(path string) (SupplyExclusions, error) {
+	exclusions := SupplyExclusions{}
+	if path == "" {
+		return exclusions, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading supply exclusions: %w", err)
+	}
+	if err := json.Unmarshal(data, &exclusions); err != nil {
+		return nil, fmt.Errorf("error decoding supply exclusions: %w", err)
+	}
+	return exclusions, nil
+}
+
+// `marketStats` derives a token's circulating supply and USD liquidity from its main pool
+// circulating supply excludes balances held by burnAddresses, the token's configured exclusions,
+// and its main pool's vault, falling back to the total supply when they cannot be read.
+// Liquidity is the USD value held by the main pool, nil when the token has no pool or it cannot be read.
+func (ts *TokenService) marketStats(ctx context.Context, tokenAddress string, supply float64) (circulating float64, liquidity *float64) {
+	addresses := append(append([]string{}, burnAddresses...), ts.exclusions[tokenAddress]...)
+
+	pool, err := ts.solanaRepo.GetLiquidityPool(ctx, tokenAddress)
+	switch {
+	case err == nil:
+		addresses = append(addresses, poolTokenVault(pool, tokenAddress))
+		liquidity = &pool.LiquidityUSD
+	case !errors.Is(err, domain.ErrPoolNotFound):
+		log.Printf("unable to fetch pool for %s: %v", tokenAddress, err)
+	}
+
+	held, err := ts.solanaRepo.GetHeldBalance(ctx, tokenAddress, addresses)
+	if err != nil {
+		log.Printf("unable to fetch excluded supply for %s: %v", tokenAddress, err)
+		return supply, liquidity
+	}
+	return max(supply-held, 0), liquidity
+}
+
+// `poolTokenVault` returns the vault of a pool holding tokenAddress
+func poolTokenVault(pool domain.LiquidityPool, tokenAddress string) string {
+	if pool.QuoteMint == tokenAddress {
+		return pool.QuoteVault
+	}
+	return pool.BaseVault
+}
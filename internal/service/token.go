@@ -3,37 +3,140 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"sync"
 	"time"
 
 	"github.com/jakobsym/aura/internal/domain"
 	"github.com/jakobsym/aura/internal/repository"
 )
 
+const (
+	// how long a cached token price is served before being refetched
+	tokenPriceTTL = 30 * time.Second
+	// how long cached supply, circulating supply and liquidity are served before being remeasured
+	tokenMarketTTL = 5 * time.Minute
+)
+
 // `TokenSerivce` provides business logic for token operations by receiving data
 // from PostgresTokenRepo and SolanaTokenRepo
 type TokenService struct {
 	psqlRepo   repository.PostgresTokenRepo
 	solanaRepo repository.SolanaTokenRepo
+	quotes     *QuoteConverter
+	exclusions SupplyExclusions
+
+	ageMu      sync.Mutex
+	ageLookups map[string]*ageLookup // token address -> running, or recently finished, age lookup
 }
 
 // `NewTokenService` creates and returns a new TokenService with required dependencies
-func NewTokenService(r repository.PostgresTokenRepo, sr repository.SolanaTokenRepo) *TokenService {
-	return &TokenService{psqlRepo: r, solanaRepo: sr}
+func NewTokenService(r repository.PostgresTokenRepo, sr repository.SolanaTokenRepo, qc *QuoteConverter, ex SupplyExclusions) *TokenService {
+	return &TokenService{psqlRepo: r, solanaRepo: sr, quotes: qc, exclusions: ex, ageLookups: make(map[string]*ageLookup)}
+}
+
+// `GetTokenData` retrieves token metadata with Price, MarketCap and FDV denominated in quote (USD by default)
+// refresh bypasses the cache and refetches every field but the creation time.
+func (ts *TokenService) GetTokenData(ctx context.Context, tokenAddress string, refresh bool, quote string) (*domain.TokenResponse, error) {
+	quote, err := ParseQuote(quote)
+	if err != nil {
+		return nil, err
+	}
+	token, err := ts.getTokenData(ctx, tokenAddress, refresh)
+	if err != nil {
+		return nil, err
+	}
+	rate, err := ts.quotes.Rate(ctx, quote)
+	if err != nil {
+		return nil, err
+	}
+	token.Price /= rate
+	token.MarketCap /= rate
+	token.FDV /= rate
+	token.Quote = quote
+	return token, nil
 }
 
-// `GetTokenData` retrieves token metadata from multiple sources
-// concurrently fetches metadata, supply, price, and age data from Solana
-// calculates FDV and persists the data
-func (ts *TokenService) GetTokenData(ctx context.Context, tokenAddress string) (*domain.TokenResponse, error) {
+// `getTokenData` retrieves token metadata in USD, reading through the tokens table
+// cached name, symbol and age are served from the DB, with the price refreshed once older than tokenPriceTTL
+// and supply, circulating supply and liquidity once older than tokenMarketTTL. refresh bypasses the cache and refetches every field,
+// except the creation time which never changes and is kept once found.
+func (ts *TokenService) getTokenData(ctx context.Context, tokenAddress string, refresh bool) (*domain.TokenResponse, error) {
+	cached, err := ts.psqlRepo.GetToken(tokenAddress)
+	switch {
+	case err == nil && !refresh:
+		return ts.refreshCachedToken(ctx, cached), nil
+	case err != nil && !errors.Is(err, domain.ErrTokenNotFound):
+		log.Printf("unable to read token %s from db: %v", tokenAddress, err)
+	}
+
+	token, err := ts.fetchTokenData(ctx, tokenAddress, cached.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := ts.psqlRepo.UpsertToken(*token); err != nil {
+		log.Printf("unable to store token in db: %v", err)
+	}
+	return token, nil
+}
+
+// `refreshCachedToken` refetches the price of a cached token once it is older than tokenPriceTTL,
+// remeasures its supplies and liquidity once older than tokenMarketTTL,
+// and picks up its age when it was never determined (i.e: cached by an earlier version) without waiting on the lookup,
+// persisting any of them, and deriving market cap and FDV from the supplies.
+// When a refetch fails the cached value is served, its price_updated_at or market_updated_at showing its age
+func (ts *TokenService) refreshCachedToken(ctx context.Context, token domain.TokenResponse) *domain.TokenResponse {
+	changed := false
+	if token.AgeSource == "" {
+		if createdAt, ok := ts.tokenAge(ctx, token.Address, 0); ok {
+			token.CreatedAt, token.AgeSource = createdAt, domain.AgeSourceCreationTxn
+			changed = true
+		}
+	}
+	if time.Since(token.PriceUpdatedAt) >= tokenPriceTTL {
+		if price, err := ts.solanaRepo.GetTokenPrice(ctx, token.Address); err != nil {
+			log.Printf("unable to refresh price of %s, serving cached price: %v", token.Address, err)
+		} else {
+			token.Price = price.Price
+			token.PriceProvider = price.Provider
+			token.PriceUpdatedAt = price.QuotedAt
+			changed = true
+		}
+	}
+	if time.Since(token.MarketUpdatedAt) >= tokenMarketTTL {
+		if supply, err := ts.solanaRepo.GetTokenSupply(ctx, token.Address); err != nil {
+			log.Printf("unable to refresh supply of %s, serving cached supply: %v", token.Address, err)
+		} else {
+			token.Supply = supply
+			token.CirculatingSupply, token.Liquidity = ts.marketStats(ctx, token.Address, supply)
+			token.MarketUpdatedAt = time.Now().UTC()
+			changed = true
+		}
+	}
+	if changed {
+		if err := ts.psqlRepo.UpsertToken(token); err != nil {
+			log.Printf("unable to store token in db: %v", err)
+		}
+	}
+	token.MarketCap = token.Price * token.CirculatingSupply
+	token.FDV = ts.solanaRepo.GetTokenFDV(ctx, token.Price, token.Supply)
+	return &token
+}
+
+// `fetchTokenData` retrieves token metadata from multiple sources
+// concurrently fetches metadata, supply, price, age, and mint data from Solana
+// the age lookup is skipped when a createdAt is already known, and a token whose age is not found within tokenAgeWait is served with a zero CreatedAt
+// derives circulating supply, market cap and FDV, and resolves the off-chain metadata the token's URI points to
+func (ts *TokenService) fetchTokenData(ctx context.Context, tokenAddress string, createdAt time.Time) (*domain.TokenResponse, error) {
 	var (
 		age struct {
-			age time.Time
-			err error
+			age    time.Time
+			source string
 		}
 		price struct {
-			price float64
+			price domain.TokenPrice
 			err   error
 		}
 		supply struct {
@@ -41,43 +144,44 @@ func (ts *TokenService) GetTokenData(ctx context.Context, tokenAddress string) (
 			err    error
 		}
 		md struct {
-			metadata []string
+			metadata domain.TokenMetadata
 			err      error
 		}
-		hasPrice  bool
-		hasSupply bool
-		recieved  int
-		fdv       float64
+		mint struct {
+			info domain.MintInfo
+			err  error
+		}
 	)
 
-	socials := fmt.Sprintf("https://x.com/search?q=%s", tokenAddress)
-
 	// buffered channels for concurrent data retrieval
 	supplyCh := make(chan struct {
 		supply float64
 		err    error
 	}, 1)
 	priceCh := make(chan struct {
-		price float64
+		price domain.TokenPrice
 		err   error
 	}, 1)
 	ageCh := make(chan struct {
-		age time.Time
-		err error
+		age    time.Time
+		source string
 	}, 1)
 	metadataCh := make(chan struct {
-		metadata []string
+		metadata domain.TokenMetadata
 		err      error
 	}, 1)
+	mintCh := make(chan struct {
+		info domain.MintInfo
+		err  error
+	}, 1)
 
 	// concurrent data retrieval
 	go func() {
-		md, err := ts.solanaRepo.GetTokenNameAndSymbol(ctx, tokenAddress)
+		md, err := ts.solanaRepo.GetTokenMetadata(ctx, tokenAddress)
 		metadataCh <- struct {
-			metadata []string
+			metadata domain.TokenMetadata
 			err      error
 		}{md, err}
-		recieved++
 	}()
 	go func() {
 		supply, err := ts.solanaRepo.GetTokenSupply(ctx, tokenAddress)
@@ -85,36 +189,47 @@ func (ts *TokenService) GetTokenData(ctx context.Context, tokenAddress string) (
 			supply float64
 			err    error
 		}{supply, err}
-		recieved++
-		hasSupply = true
 	}()
 
 	go func() {
 		price, err := ts.solanaRepo.GetTokenPrice(ctx, tokenAddress)
 		priceCh <- struct {
-			price float64
+			price domain.TokenPrice
 			err   error
 		}{price, err}
-		recieved++
 	}()
 
 	go func() {
-		age, err := ts.solanaRepo.GetTokenAge(ctx, tokenAddress)
+		if !createdAt.IsZero() {
+			ageCh <- struct {
+				age    time.Time
+				source string
+			}{createdAt, domain.AgeSourceCreationTxn}
+			return
+		}
+		age, ok := ts.tokenAge(ctx, tokenAddress, tokenAgeWait)
+		source := ""
+		if ok {
+			source = domain.AgeSourceCreationTxn
+		}
 		ageCh <- struct {
-			age time.Time
-			err error
-		}{age, err}
-		recieved++
-		hasPrice = true
+			age    time.Time
+			source string
+		}{age, source}
+	}()
+
+	go func() {
+		info, err := ts.solanaRepo.GetMintInfo(ctx, tokenAddress)
+		mintCh <- struct {
+			info domain.MintInfo
+			err  error
+		}{info, err}
 	}()
 
 	// process channels as they are filled
-	for {
+	for range 5 {
 		select {
 		case age = <-ageCh:
-			if age.err != nil {
-				return nil, fmt.Errorf("failed to fetch age: %w", age.err)
-			}
 		case supply = <-supplyCh:
 			if supply.err != nil {
 				return nil, fmt.Errorf("failed to supply: %w", supply.err)
@@ -127,34 +242,40 @@ func (ts *TokenService) GetTokenData(ctx context.Context, tokenAddress string) (
 			if md.err != nil {
 				return nil, fmt.Errorf("failed to fetch metadata: %w", md.err)
 			}
-		}
-
-		if hasPrice && hasSupply {
-			fdv = ts.solanaRepo.GetTokenFDV(ctx, price.price, supply.supply)
-		}
-
-		if recieved == 4 {
-			break
+		case mint = <-mintCh:
+			// program and extensions are optional, tokens are served without them when unavailable
+			if mint.err != nil {
+				log.Printf("unable to fetch mint info for %s: %v", tokenAddress, mint.err)
+			}
 		}
 	}
 
 	token := &domain.TokenResponse{
-		Address:   tokenAddress,
-		Name:      md.metadata[0],
-		Symbol:    md.metadata[1],
-		CreatedAt: age.age,
-		Supply:    supply.supply,
-		Price:     price.price,
-		FDV:       fdv,
-		Socials:   socials,
+		Address:        tokenAddress,
+		Name:           md.metadata.Name,
+		Symbol:         md.metadata.Symbol,
+		CreatedAt:      age.age,
+		AgeSource:      age.source,
+		Supply:         supply.supply,
+		Price:          price.price.Price,
+		PriceProvider:  price.price.Provider,
+		PriceUpdatedAt: price.price.QuotedAt,
+		FDV:            ts.solanaRepo.GetTokenFDV(ctx, price.price.Price, supply.supply),
+		Program:        mint.info.Program,
+		Extensions:     mint.info.Extensions,
 	}
+	token.CirculatingSupply, token.Liquidity = ts.marketStats(ctx, tokenAddress, supply.supply)
+	token.MarketUpdatedAt = time.Now().UTC()
+	token.MarketCap = token.Price * token.CirculatingSupply
 
-	// insert this token into DB
-	err := ts.psqlRepo.CreateToken(*token)
-	if err != nil {
-		log.Printf("unable to store token in db")
+	// off-chain metadata is optional, tokens are served without it when unavailable
+	if md.metadata.URI != "" {
+		offChain, err := ts.solanaRepo.GetOffChainMetadata(ctx, md.metadata.URI)
+		if err != nil {
+			log.Printf("unable to fetch off-chain metadata for %s: %v", tokenAddress, err)
+		}
+		token.OffChainMetadata = offChain
 	}
-
 	return token, nil
 }
 
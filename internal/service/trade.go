@@ -0,0 +1,86 @@
+// Package `service` calls repository methods to implement business logic
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/jakobsym/aura/internal/domain"
+	"github.com/jakobsym/aura/internal/repository"
+)
+
+// `quoteMints` holds the mints trades are denominated in
+var quoteMints = map[string]bool{
+	domain.USDCMint:       true,
+	domain.USDTMint:       true,
+	domain.WrappedSOLMint: true,
+}
+
+// `newTrade` builds a Trade from a swap detected on walletAddress
+func newTrade(walletAddress, signature string, slot uint64, blockTime time.Time, swap domain.SwapResult) domain.Trade {
+	return domain.Trade{
+		Signature:     signature,
+		WalletAddress: walletAddress,
+		Slot:          slot,
+		BlockTime:     blockTime,
+		InputMint:     swap.SentAddress,
+		InputAmount:   swap.SentAmount,
+		OutputMint:    swap.ReceivedAddress,
+		OutputAmount:  swap.ReceivedAmount,
+		Venue:         swap.Venue,
+	}
+}
+
+// `recordTrade` values a live trade in USD and persists it
+// trades are valued from their stablecoin side when present,
+// otherwise from the current price of the input, then output, token
+func recordTrade(ctx context.Context, tradeRepo repository.TradeRepo, tokenRepo repository.SolanaTokenRepo, trade domain.Trade) error {
+	if value, ok := stablecoinValue(trade); ok {
+		trade.USDValue, trade.Priced = value, true
+	} else if price, err := tokenRepo.GetTokenPrice(ctx, trade.InputMint); err == nil {
+		trade.USDValue, trade.Priced = price.Price*trade.InputAmount, true
+	} else if price, err := tokenRepo.GetTokenPrice(ctx, trade.OutputMint); err == nil {
+		trade.USDValue, trade.Priced = price.Price*trade.OutputAmount, true
+	}
+	return tradeRepo.CreateTrade(trade)
+}
+
+// `recordHistoricalTrade` values a backfilled trade in USD and persists it
+// trades are valued from their stablecoin side, or their SOL side at the SOL price of the block time.
+// Trades with neither side, or without a SOL price at that time, are stored unpriced.
+func recordHistoricalTrade(ctx context.Context, tradeRepo repository.TradeRepo, history repository.SOLPriceHistory, trade domain.Trade) error {
+	if value, ok := stablecoinValue(trade); ok {
+		trade.USDValue, trade.Priced = value, true
+	} else if solAmount, ok := solValue(trade); ok {
+		price, err := history.GetSOLPriceAt(ctx, trade.BlockTime)
+		if err != nil {
+			log.Printf("storing %s unpriced, no SOL price at %s: %v", trade.Signature, trade.BlockTime, err)
+		} else {
+			trade.USDValue, trade.Priced = price*solAmount, true
+		}
+	}
+	return tradeRepo.CreateTrade(trade)
+}
+
+// `stablecoinValue` returns the amount of a trade's USD stablecoin side
+func stablecoinValue(trade domain.Trade) (float64, bool) {
+	switch {
+	case domain.USDStablecoins[trade.InputMint]:
+		return trade.InputAmount, true
+	case domain.USDStablecoins[trade.OutputMint]:
+		return trade.OutputAmount, true
+	}
+	return 0, false
+}
+
+// `solValue` returns the amount of a trade's SOL side
+func solValue(trade domain.Trade) (float64, bool) {
+	switch domain.WrappedSOLMint {
+	case trade.InputMint:
+		return trade.InputAmount, true
+	case trade.OutputMint:
+		return trade.OutputAmount, true
+	}
+	return 0, false
+}
@@ -0,0 +1,183 @@
+// Package `service` calls repository methods to implement business logic
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/jakobsym/aura/internal/domain"
+	"github.com/jakobsym/aura/internal/repository"
+)
+
+// trade history page sizes
+const (
+	defaultTradeLimit = 50
+	maxTradeLimit     = 200
+)
+
+const (
+	// USD value below which priced holdings are omitted, overridden by PORTFOLIO_DUST_USD
+	defaultDustThreshold = 1.0
+	// number of holdings enriched with metadata and price at once
+	enrichConcurrency = 8
+	// how long a portfolio request spends enriching its holdings, holdings left by then are served unpriced
+	enrichTimeout = 10 * time.Second
+)
+
+// `WalletService` provides business logic for wallet activity by receiving data
+// from the Postgres TradeRepo, SolanaTokenRepo, and SolanaWalletRepo
+type WalletService struct {
+	tradeRepo  repository.TradeRepo
+	tokenRepo  repository.SolanaTokenRepo
+	walletRepo repository.SolanaWalletRepo
+	quotes     *QuoteConverter
+}
+
+// `NewWalletService` creates and returns a new WalletService with required dependencies
+func NewWalletService(tr repository.TradeRepo, tkr repository.SolanaTokenRepo, wr repository.SolanaWalletRepo, qc *QuoteConverter) *WalletService {
+	return &WalletService{tradeRepo: tr, tokenRepo: tkr, walletRepo: wr, quotes: qc}
+}
+
+// `GetTrades` retrieves a page of a wallet's trade history, newest first
+// the page size is clamped between 1 and maxTradeLimit
+func (ws *WalletService) GetTrades(ctx context.Context, filter domain.TradeFilter) (domain.TradePage, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultTradeLimit
+	}
+	filter.Limit = min(filter.Limit, maxTradeLimit)
+	return ws.tradeRepo.GetTrades(filter)
+}
+
+// `GetPnL` computes a wallet's per token positions and PnL from its trade history
+// using the given cost basis method (fifo by default).
+// Unrealized PnL values open positions at the current token price.
+// Values are tracked in USD, other quotes are rejected as trades would need converting at their own block time rates.
+func (ws *WalletService) GetPnL(ctx context.Context, walletAddress, method, quote string) (domain.WalletPnL, error) {
+	if method == "" {
+		method = domain.PnLFIFO
+	}
+	if method != domain.PnLFIFO && method != domain.PnLAverage {
+		return domain.WalletPnL{}, domain.ErrInvalidPnLMethod
+	}
+	quote, err := ParseQuote(quote)
+	if err != nil {
+		return domain.WalletPnL{}, err
+	}
+	if quote != domain.QuoteUSD {
+		return domain.WalletPnL{}, fmt.Errorf("%w: %s", domain.ErrInvalidPnLQuote, quote)
+	}
+	trades, err := ws.tradeRepo.GetTradeHistory(walletAddress)
+	if err != nil {
+		return domain.WalletPnL{}, err
+	}
+
+	res := domain.WalletPnL{WalletAddress: walletAddress, Method: method, Quote: quote, Positions: []domain.TokenPosition{}}
+	for _, p := range buildPositions(trades, method) {
+		tp := p.summary()
+		if tp.Quantity > positionDustTolerance {
+			price, err := ws.tokenRepo.GetTokenPrice(ctx, tp.Mint)
+			if err != nil {
+				log.Printf("failed to price %s: %v", tp.Mint, err)
+			} else {
+				tp.Price = price.Price
+				tp.UnrealizedPnL = price.Price*tp.Quantity - tp.CostBasis
+			}
+		}
+		res.RealizedPnL += tp.RealizedPnL
+		res.UnrealizedPnL += tp.UnrealizedPnL
+		res.Positions = append(res.Positions, tp)
+	}
+	return res, nil
+}
+
+// `GetPortfolio` retrieves a wallet's SOL balance and token holdings valued at current prices
+// holdings are sorted by value, and holdings worth less than the dust threshold omitted.
+// Holdings without a price are omitted as well, unless includeUnpriced is set.
+// Prices and values are denominated in quote (USD by default), the dust threshold applies in USD.
+// Holdings are enriched for at most enrichTimeout, whichever are not priced by then count as unpriced.
+func (ws *WalletService) GetPortfolio(ctx context.Context, walletAddress, quote string, includeUnpriced bool) (domain.Portfolio, error) {
+	quote, err := ParseQuote(quote)
+	if err != nil {
+		return domain.Portfolio{}, err
+	}
+	rate, err := ws.quotes.Rate(ctx, quote)
+	if err != nil {
+		return domain.Portfolio{}, err
+	}
+	solBalance, err := ws.walletRepo.GetSolBalance(ctx, walletAddress)
+	if err != nil {
+		return domain.Portfolio{}, err
+	}
+	holdings, err := ws.walletRepo.GetTokenHoldings(ctx, walletAddress)
+	if err != nil {
+		return domain.Portfolio{}, err
+	}
+
+	enrichCtx, cancel := context.WithTimeout(ctx, enrichTimeout)
+	defer cancel()
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, enrichConcurrency)
+	for i := range holdings {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+			case <-enrichCtx.Done():
+				return
+			}
+			defer func() { <-sem }()
+			ws.enrichHolding(enrichCtx, &holdings[i])
+		}()
+	}
+	wg.Wait()
+	if enrichCtx.Err() != nil {
+		log.Printf("portfolio of %s not fully priced within %s", walletAddress, enrichTimeout)
+	}
+
+	portfolio := domain.Portfolio{WalletAddress: walletAddress, Quote: quote, SOLBalance: solBalance, Holdings: []domain.TokenHolding{}}
+	if price, err := ws.tokenRepo.GetTokenPrice(ctx, domain.WrappedSOLMint); err != nil {
+		log.Printf("failed to price SOL: %v", err)
+	} else {
+		portfolio.SOLValue = price.Price * solBalance / rate
+	}
+	portfolio.TotalValue = portfolio.SOLValue
+
+	dust := envFloat("PORTFOLIO_DUST_USD", defaultDustThreshold)
+	for _, holding := range holdings {
+		unpriced := holding.Price <= 0
+		if unpriced && !includeUnpriced || !unpriced && holding.Value < dust {
+			continue
+		}
+		holding.Price /= rate
+		holding.Value /= rate
+		portfolio.Holdings = append(portfolio.Holdings, holding)
+		portfolio.TotalValue += holding.Value
+	}
+	slices.SortStableFunc(portfolio.Holdings, func(a, b domain.TokenHolding) int {
+		switch {
+		case a.Value > b.Value:
+			return -1
+		case a.Value < b.Value:
+			return 1
+		}
+		return 0
+	})
+	return portfolio, nil
+}
+
+// `enrichHolding` fills in a holding's name, symbol, price and USD value
+// tokens without Metaplex metadata or a price are left without them
+func (ws *WalletService) enrichHolding(ctx context.Context, holding *domain.TokenHolding) {
+	if metadata, err := ws.tokenRepo.GetTokenMetadata(ctx, holding.Mint); err == nil {
+		holding.Name, holding.Symbol = metadata.Name, metadata.Symbol
+	}
+	if price, err := ws.tokenRepo.GetTokenPrice(ctx, holding.Mint); err == nil {
+		holding.Price = price.Price
+		holding.Value = price.Price * holding.Amount
+	}
+}